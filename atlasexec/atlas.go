@@ -16,17 +16,40 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 type (
-	// Client is a client for the Atlas CLI.
+	// Client is a client for the Atlas CLI. It is safe for concurrent use by
+	// multiple goroutines. Use the With method, or pass options to the command
+	// methods, to override the client configuration for a specific command.
 	Client struct {
+		mu sync.RWMutex // Guards the settings from the Set* methods.
+		settings
+	}
+	// settings holds the configuration of the Client.
+	settings struct {
 		execPath   string
 		workingDir string
 		env        Environ
+		extraEnv   Environ
 		stdout     io.Writer
 		stderr     io.Writer
-	}
+		timeout    time.Duration
+		executor   Executor
+		hooks      []Hook
+		version    *versionProbe
+		retry      *RetryPolicy
+		onStderr   func(context.Context, *StderrEvent)
+		defaults   *Defaults
+		secretVars map[string]bool
+		// Secrets passed using environment variables.
+		secretsFromEnv bool
+		cloudToken     string
+	}
+	// ClientOption allows configuring the Client on creation, when it
+	// is derived using the With method, or for a single command.
+	ClientOption func(*Client)
 	// LoginParams are the parameters for the `login` command.
 	LoginParams struct {
 		Token string
@@ -79,6 +102,16 @@ type (
 	}
 	// TriggerType defines the type for the "trigger_type" enum field.
 	TriggerType string
+	// TxMode defines the transaction mode of the 'migrate apply' and 'schema apply' commands.
+	// See: https://atlasgo.io/versioned/apply#transaction-configuration
+	TxMode string
+	// DirFormat defines the format of a migration directory.
+	// See: https://atlasgo.io/versioned/apply#migration-directory-format
+	DirFormat string
+	// LintFormat defines the output format of the lint commands. Besides the predefined
+	// values, it accepts any Go template, for example: "{{ range .Files }}{{ .Name }}{{ end }}".
+	// See: https://atlasgo.io/cli-reference#atlas-migrate-lint
+	LintFormat string
 	// Vars is a map of variables for the command.
 	//
 	// Deprecated: Use Vars2 instead.
@@ -112,23 +145,126 @@ const (
 	ExecOrderNonLinear  MigrateExecOrder = "non-linear"
 )
 
+// TxMode values.
+const (
+	TxModeFile TxMode = "file" // Default
+	TxModeAll  TxMode = "all"
+	TxModeNone TxMode = "none"
+)
+
+// DirFormat values.
+const (
+	DirFormatAtlas         DirFormat = "atlas" // Default
+	DirFormatGolangMigrate DirFormat = "golang-migrate"
+	DirFormatGoose         DirFormat = "goose"
+	DirFormatFlyway        DirFormat = "flyway"
+	DirFormatLiquibase     DirFormat = "liquibase"
+	DirFormatDBMate        DirFormat = "dbmate"
+)
+
+// LintFormat values.
+const (
+	LintFormatJSON LintFormat = "{{ json . }}" // Default
+	LintFormatURL  LintFormat = "{{ .URL }}"   // URL of the report uploaded to Atlas Cloud.
+)
+
 // NewClient returns a new Atlas client with the given atlas-cli path.
-func NewClient(workingDir, execPath string) (_ *Client, err error) {
+func NewClient(workingDir, execPath string, opts ...ClientOption) (_ *Client, err error) {
+	c := &Client{settings: settings{workingDir: workingDir}}
+	for _, opt := range opts {
+		opt(c)
+	}
 	if execPath == "" {
 		return nil, fmt.Errorf("execPath cannot be empty")
-	} else if execPath, err = exec.LookPath(execPath); err != nil {
-		return nil, fmt.Errorf("looking up atlas-cli: %w", err)
 	}
-	if workingDir != "" {
-		_, err := os.Stat(workingDir)
+	// Custom executors are responsible for resolving the path.
+	switch c.executor.(type) {
+	case nil, ProcessExecutor, *ProcessExecutor:
+		if execPath, err = exec.LookPath(execPath); err != nil {
+			return nil, fmt.Errorf("looking up atlas-cli: %w", err)
+		}
+		if c.executor == nil {
+			c.executor = ProcessExecutor{}
+		}
+	}
+	if c.workingDir != "" {
+		_, err := os.Stat(c.workingDir)
 		if err != nil {
-			return nil, fmt.Errorf("initializing Atlas with working dir %q: %w", workingDir, err)
+			return nil, fmt.Errorf("initializing Atlas with working dir %q: %w", c.workingDir, err)
 		}
 	}
-	return &Client{
-		execPath:   execPath,
-		workingDir: workingDir,
-	}, nil
+	c.execPath = execPath
+	return c, nil
+}
+
+// WithExecutor configures the Client to run the atlas-cli using the given Executor.
+// When set, the exec path is passed to the executor as-is and is not looked up in PATH.
+func WithExecutor(e Executor) ClientOption {
+	return func(c *Client) {
+		c.executor = e
+	}
+}
+
+// WithWorkingDir sets the working directory of the commands.
+func WithWorkingDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.workingDir = dir
+	}
+}
+
+// WithEnv adds the given environment variables on top of the client
+// environment, that is, the OS environment or the one set by SetEnv.
+func WithEnv(env Environ) ClientOption {
+	return func(c *Client) {
+		extra := maps.Clone(c.extraEnv)
+		if extra == nil {
+			extra = make(Environ, len(env))
+		}
+		maps.Copy(extra, env)
+		c.extraEnv = extra
+	}
+}
+
+// WithStdout specifies a writer to stream stdout to.
+func WithStdout(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.stdout = w
+	}
+}
+
+// WithStderr specifies a writer to stream stderr to.
+func WithStderr(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.stderr = w
+	}
+}
+
+// WithTimeout limits the execution time of the commands.
+// A zero duration means no timeout.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// With returns a copy of the client with the given options applied.
+// The original client is not modified.
+//
+// Example:
+//
+//	staging := client.With(atlasexec.WithWorkingDir("staging"))
+//	_, err := staging.MigrateApply(ctx, &atlasexec.MigrateApplyParams{})
+func (c *Client) With(opts ...ClientOption) *Client {
+	c.mu.RLock()
+	n := &Client{settings: c.settings}
+	c.mu.RUnlock()
+	// Ensure options that append to the hooks do
+	// not share the backing array with the parent.
+	n.hooks = slices.Clip(n.hooks)
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
 }
 
 // WithWorkDir creates a new client with the given working directory.
@@ -143,10 +279,7 @@ func NewClient(workingDir, execPath string) (_ *Client, err error) {
 //	  return err
 //	})
 func (c *Client) WithWorkDir(dir string, fn func(*Client) error) error {
-	wd := c.workingDir
-	defer func() { c.workingDir = wd }()
-	c.workingDir = dir
-	return fn(c)
+	return fn(c.With(WithWorkingDir(dir)))
 }
 
 // SetEnv allows we override the environment variables for the atlas-cli.
@@ -157,55 +290,87 @@ func (c *Client) SetEnv(env map[string]string) error {
 			return fmt.Errorf("atlasexec: cannot override the default environment variable %q", k)
 		}
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.env = env
 	return nil
 }
 
 // SetStdout specifies a writer to stream stdout to for every command.
 func (c *Client) SetStdout(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.stdout = w
 }
 
 // SetStderr specifies a writer to stream stderr to for every command.
 func (c *Client) SetStderr(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.stderr = w
 }
 
 // Login runs the 'login' command.
-func (c *Client) Login(ctx context.Context, params *LoginParams) error {
-	if params.Token == "" {
-		return errors.New("token cannot be empty")
+func (c *Client) Login(ctx context.Context, params *LoginParams, opts ...ClientOption) error {
+	c = c.With(opts...)
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return err
 	}
-	_, err := c.runCommand(ctx, []string{"login", "--token", params.Token})
+	_, err = c.runCommand(ctx, args)
 	return err
 }
 
+// AsArgs returns the parameters as arguments.
+func (p *LoginParams) AsArgs() ([]string, error) {
+	return []string{"login", "--token", p.Token}, nil
+}
+
 // Logout runs the 'logout' command.
-func (c *Client) Logout(ctx context.Context) error {
+func (c *Client) Logout(ctx context.Context, opts ...ClientOption) error {
+	c = c.With(opts...)
 	_, err := c.runCommand(ctx, []string{"logout"})
 	return err
 }
 
 // WhoAmI runs the 'whoami' command.
-func (c *Client) WhoAmI(ctx context.Context, params *WhoAmIParams) (*WhoAmI, error) {
+func (c *Client) WhoAmI(ctx context.Context, params *WhoAmIParams, opts ...ClientOption) (*WhoAmI, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	return firstResult(jsonDecode[WhoAmI](c.runCommand(ctx, args)))
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *WhoAmIParams) AsArgs() ([]string, error) {
 	args := []string{"whoami", "--format", "{{ json . }}"}
 	// Global flags
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
 	}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	return firstResult(jsonDecode[WhoAmI](c.runCommand(ctx, args)))
+	return args, nil
 }
 
 var reVersion = regexp.MustCompile(`^atlas version v(\d+\.\d+.\d+)-?([a-z0-9]*)?`)
 
 // Version runs the 'version' command.
-func (c *Client) Version(ctx context.Context) (*Version, error) {
+func (c *Client) Version(ctx context.Context, opts ...ClientOption) (*Version, error) {
+	c = c.With(opts...)
 	r, err := c.runCommand(ctx, []string{"version"})
 	if err != nil {
 		return nil, err
@@ -214,19 +379,11 @@ func (c *Client) Version(ctx context.Context) (*Version, error) {
 	if err != nil {
 		return nil, err
 	}
-	v := reVersion.FindSubmatch(out)
-	if v == nil {
+	v, err := ParseVersion(string(out))
+	if err != nil {
 		return nil, errors.New("unexpected output format")
 	}
-	var sha string
-	if len(v) > 2 {
-		sha = string(v[2])
-	}
-	return &Version{
-		Version: string(v[1]),
-		SHA:     sha,
-		Canary:  strings.Contains(string(out), "canary"),
-	}, nil
+	return v, nil
 }
 
 // var reVersion = regexp.MustCompile(`^atlas version v(\d+\.\d+.\d+)-?([a-z0-9]*)?`)
@@ -283,11 +440,32 @@ var ErrRequireLogin = errors.New("command requires 'atlas login'")
 
 // runCommand runs the given command and returns its output.
 func (c *Client) runCommand(ctx context.Context, args []string) (io.Reader, error) {
+	if err := c.checkVersion(ctx, args); err != nil {
+		return nil, err
+	}
+	base, err := c.invocation(args)
+	if err != nil {
+		return nil, err
+	}
 	var stdout, stderr bytes.Buffer
-	cmd := c.cmd(ctx, args)
-	cmd.Stdout = mergeWriters(&stdout, c.stdout)
-	cmd.Stderr = mergeWriters(&stderr, c.stderr)
-	if err := c.runErr(cmd.Run(), &stdout, &stderr); err != nil {
+	err = c.withRetry(ctx, args, func() error {
+		stdout.Reset()
+		stderr.Reset()
+		ctx := ctx
+		// The timeout applies to each attempt.
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+		inv := *base
+		events, flush := c.stderrEvents(ctx, &inv)
+		defer flush()
+		inv.Stdout = mergeWriters(&stdout, c.stdout)
+		inv.Stderr = mergeWriters(&stderr, c.stderr, events)
+		return c.exec(ctx, &inv, &stdout, &stderr)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &stdout, nil
@@ -301,43 +479,82 @@ type Stream[T any] interface {
 	Current() (T, error)
 	// Err returns the error, if any, that occurred while reading the stream.
 	Err() error
+	// Close stops the command if it is still running, and releases the resources of
+	// the stream. It returns the final error of the command, like Err. Streams that
+	// are not fully consumed must be closed to avoid leaking the atlas-cli process.
+	Close() error
 }
 
 // runCommandStream runs the given command streams its output split by new-lines.
 func (c *Client) runCommandStream(ctx context.Context, args []string) (Stream[string], error) {
-	cmd := c.cmd(ctx, args)
-	var stderr bytes.Buffer
-	cmd.Stderr = mergeWriters(&stderr, c.stderr)
-	out, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("creating stdout pipe: %w", err)
+	var cancel context.CancelFunc
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	if err := c.checkVersion(ctx, args); err != nil {
+		cancel()
+		return nil, err
 	}
-	if err = cmd.Start(); err != nil {
-		return nil, fmt.Errorf("starting command: %w", err)
+	inv, err := c.invocation(args)
+	if err != nil {
+		cancel()
+		return nil, err
 	}
 	var (
-		scan   = bufio.NewScanner(out)
-		buf    = strings.Builder{}
+		stderr bytes.Buffer
+		buf    strings.Builder
+		pr, pw = io.Pipe()
+		done   = make(chan error, 1)
+	)
+	// The stdout is recorded by the executor, as the decoded
+	// error is computed (and passed to the hooks) before the
+	// stream is fully consumed.
+	events, flush := c.stderrEvents(ctx, inv)
+	inv.Stdout = io.MultiWriter(&buf, pw)
+	inv.Stderr = mergeWriters(&stderr, c.stderr, events)
+	go func() {
+		defer cancel()
+		err := c.exec(ctx, inv, &buf, &stderr)
+		flush()
+		pw.Close()
+		done <- err
+	}()
+	var (
+		scan   = bufio.NewScanner(pr)
 		ch     = make(chan string)
-		s      = &stream{ch: ch}
-		stdout = mergeWriters(&buf, c.stdout)
+		s      = &stream{ch: ch, cancel: cancel, closed: make(chan struct{}), finished: make(chan struct{})}
+		stdout = mergeWriters(c.stdout)
 	)
 	go func() {
+		defer close(s.finished)
 		defer close(ch)
+	scan:
 		for scan.Scan() {
 			stdout.Write(scan.Bytes())
-			ch <- scan.Text()
+			select {
+			case ch <- scan.Text():
+			case <-s.closed:
+				break scan
+			}
 		}
+		// Unblock the executor in case the scanner stopped early.
+		pr.Close()
+		err := <-done
 		s.lock.Lock()
 		defer s.lock.Unlock()
-		s.err = c.runErr(cmd.Wait(), &buf, &stderr)
+		s.err = err
 	}()
 	return s, nil
 }
 
-func (c *Client) cmd(ctx context.Context, args []string) *exec.Cmd {
-	cmd := exec.CommandContext(ctx, c.execPath, args...)
-	cmd.Dir = c.workingDir
+// invocation returns the invocation of the atlas-cli with the given arguments.
+func (c *Client) invocation(args []string) (*Invocation, error) {
+	args, secrets, err := c.secretArgs(args)
+	if err != nil {
+		return nil, err
+	}
 	var env Environ
 	if c.env == nil {
 		// Initialize the environment variables from the OS.
@@ -345,12 +562,18 @@ func (c *Client) cmd(ctx context.Context, args []string) *exec.Cmd {
 	} else {
 		env = maps.Clone(c.env)
 	}
+	maps.Copy(env, c.extraEnv)
 	maps.Copy(env, defaultEnvs)
-	cmd.Env = env.ToSlice()
-	return cmd
+	maps.Copy(env, secrets)
+	return &Invocation{
+		Path: c.execPath,
+		Args: args,
+		Env:  env.ToSlice(),
+		Dir:  c.workingDir,
+	}, nil
 }
 
-func (c *Client) runErr(err error, stdout, stderr interface{ String() string }) error {
+func (c *Client) runErr(ctx context.Context, err error, r *redactor, stdout, stderr interface{ String() string }) error {
 	if err == nil {
 		return nil
 	}
@@ -359,9 +582,15 @@ func (c *Client) runErr(err error, stdout, stderr interface{ String() string })
 	if e == "Error: command requires 'atlas login'" {
 		return ErrRequireLogin
 	}
+	class := classify(e, stdout.String())
+	if ctx.Err() != nil {
+		class = fmt.Errorf("%w: %w", ErrCanceled, context.Cause(ctx))
+	}
 	return &Error{
 		err:    err,
-		Stderr: strings.TrimSpace(stderr.String()),
+		class:  class,
+		redact: r,
+		Stderr: e,
 		Stdout: strings.TrimSpace(stdout.String()),
 	}
 }
@@ -369,28 +598,46 @@ func (c *Client) runErr(err error, stdout, stderr interface{ String() string })
 type stream struct {
 	ch   chan string
 	cur  string
+	ok   bool // Last call to Next returned true.
 	err  error
 	lock sync.RWMutex
+	// cancel stops the command, and closed is closed
+	// once the consumer closed the stream. finished is
+	// closed after the command exited and err was set.
+	cancel    context.CancelFunc
+	closed    chan struct{}
+	closeOnce sync.Once
+	finished  chan struct{}
 }
 
 // Next advances the stream to the next item.
 func (s *stream) Next() bool {
+	s.ok = false
 	s.lock.RLock()
 	if s.err != nil || s.ch == nil {
 		s.lock.RUnlock()
 		return false
 	}
 	s.lock.RUnlock()
-	r, ok := <-s.ch
-	if !ok {
+	select {
+	case r, ok := <-s.ch:
+		if !ok {
+			return false
+		}
+		s.cur, s.ok = r, true
+		return true
+	case <-s.closed:
 		return false
 	}
-	s.cur = r
-	return true
 }
 
-// Current returns the current item from the stream.
+// Current returns the current item from the stream. Once Next returned true,
+// the item is returned even if the command already failed, and the error is
+// reported after Next returns false.
 func (s *stream) Current() (string, error) {
+	if s.ok {
+		return s.cur, nil
+	}
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 	if s.err != nil {
@@ -406,6 +653,19 @@ func (s *stream) Err() error {
 	return s.err
 }
 
+// Close stops the command if it is still running, waits for it to exit, and returns its error.
+func (s *stream) Close() error {
+	if s.finished == nil {
+		return s.Err()
+	}
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.cancel()
+	})
+	<-s.finished
+	return s.Err()
+}
+
 var _ Stream[string] = (*stream)(nil)
 
 func mergeWriters(writers ...io.Writer) io.Writer {
@@ -426,25 +686,28 @@ func mergeWriters(writers ...io.Writer) io.Writer {
 }
 
 // Error is an error returned by the atlasexec package,
-// when it executes the atlas-cli command.
+// when it executes the atlas-cli command. Credentials are
+// masked in its message, but not in its Stdout and Stderr.
 type Error struct {
-	err    error  // The underlying error.
-	Stdout string // Stdout of the command.
-	Stderr string // Stderr of the command.
+	err    error     // The underlying error.
+	class  error     // The well-known failure, if recognized.
+	redact *redactor // Masks the secrets of the command.
+	Stdout string    // Stdout of the command.
+	Stderr string    // Stderr of the command.
 }
 
 // Error implements the error interface.
 func (e *Error) Error() string {
 	if e.Stderr != "" {
-		return e.Stderr
+		return e.redact.text(e.Stderr)
 	}
-	return e.Stdout
+	return e.redact.text(e.Stdout)
 }
 
 // ExitCode returns the exit code of the command.
-// If the error is not an exec.ExitError, it returns 1.
+// If the underlying error does not carry an exit code, it returns -1.
 func (e *Error) ExitCode() int {
-	var exitErr *exec.ExitError
+	var exitErr interface{ ExitCode() int }
 	if errors.As(e.err, &exitErr) {
 		return exitErr.ExitCode()
 	}
@@ -542,13 +805,13 @@ func jsonDecode[T any](r io.Reader, err error) ([]*T, error) {
 	}
 }
 
-func jsonDecodeErr[T any](fn func([]*T, string) error) func(io.Reader, error) ([]*T, error) {
+func jsonDecodeErr[T any](fn func([]*T, *Error) error) func(io.Reader, error) ([]*T, error) {
 	return func(r io.Reader, err error) ([]*T, error) {
 		if err != nil {
 			if cliErr := (&Error{}); errors.As(err, &cliErr) && cliErr.Stdout != "" {
-				d, err := jsonDecode[T](strings.NewReader(cliErr.Stdout), nil)
-				if err == nil {
-					return nil, fn(d, cliErr.Stderr)
+				d, derr := jsonDecode[T](strings.NewReader(cliErr.Stdout), nil)
+				if derr == nil {
+					return nil, replaceLast(err, fn(d, cliErr))
 				}
 				// If the error is not a JSON, return the original error.
 			}
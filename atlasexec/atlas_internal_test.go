@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -36,3 +37,20 @@ BAZ=qux
 FOO=bar
 `, string(raw))
 }
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	// Jitter is enabled by default.
+	p := &RetryPolicy{Backoff: time.Second}
+	var jittered bool
+	for range 100 {
+		d := p.delay(2)
+		require.GreaterOrEqual(t, d, 1600*time.Millisecond)
+		require.LessOrEqual(t, d, 2*time.Second)
+		jittered = jittered || d != 2*time.Second
+	}
+	require.True(t, jittered)
+	// Negative values disable it.
+	p.Jitter = -1
+	require.Equal(t, 2*time.Second, p.delay(2))
+	require.Equal(t, 8*time.Second, p.delay(4))
+}
@@ -6,9 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"ariga.io/atlas/sql/migrate"
 )
 
 type (
@@ -23,7 +31,7 @@ type (
 		URL             string
 		RevisionsSchema string
 		BaselineVersion string
-		TxMode          string
+		TxMode          TxMode
 		ExecOrder       MigrateExecOrder
 		Amount          uint64
 		AllowDirty      bool
@@ -47,6 +55,7 @@ type (
 	MigrateApplyError struct {
 		Result []*MigrateApply
 		Stderr string
+		err    error // The underlying CLI error, if exists.
 	}
 	// MigrateExecOrder define how Atlas computes and executes pending migration files to the database.
 	// See: https://atlasgo.io/versioned/apply#execution-order
@@ -97,7 +106,7 @@ type (
 		Name        string
 		Tag         string
 		DirURL      string
-		DirFormat   string
+		DirFormat   DirFormat
 		LockTimeout string
 	}
 	// MigrateLintParams are the parameters for the `migrate lint` command.
@@ -106,7 +115,7 @@ type (
 		Env       string
 		Vars      VarArgs
 		Context   *RunContext
-		Format    string
+		Format    LintFormat
 		DevURL    string
 
 		DirURL string
@@ -122,7 +131,7 @@ type (
 		Vars      VarArgs
 
 		DirURL    string
-		DirFormat string
+		DirFormat DirFormat
 	}
 	// MigrateRebaseParams are the parameters for the `migrate rebase` command.
 	MigrateRebaseParams struct {
@@ -142,7 +151,7 @@ type (
 		DevURL    string
 
 		DirURL          string
-		DirFormat       string
+		DirFormat       DirFormat
 		Run             string
 		RevisionsSchema string
 		Paths           []string
@@ -167,7 +176,7 @@ type (
 		ToURL       string
 		DevURL      string
 		DirURL      string
-		DirFormat   string
+		DirFormat   DirFormat
 		Schema      []string
 		LockTimeout string
 		Format      string
@@ -191,258 +200,459 @@ type (
 		Error     string      `json:"Error,omitempty"`     // Last Error that occurred
 		SQL       string      `json:"SQL,omitempty"`       // SQL that caused the last Error
 	}
+	// MigrateNewParams are the parameters for the `migrate new` command.
+	MigrateNewParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+
+		DirURL    string
+		DirFormat DirFormat
+		Name      string
+		// Content is the initial content of the created file. If set, the
+		// file is written and the migration directory is re-hashed.
+		Content string
+	}
+	// MigrateImportParams are the parameters for the `migrate import` command.
+	MigrateImportParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+
+		FromURL string // URL of the directory to import, e.g. "file://goose".
+		// FromFS holds the directory to import, e.g. an embed.FS. It is staged
+		// in a temporary directory, and cannot be used together with FromURL.
+		FromFS     fs.FS
+		FromFormat DirFormat // Format of the imported directory, e.g. DirFormatGoose.
+		ToURL      string    // URL of the directory to write the files to.
+	}
+	// MigrateCheckpointParams are the parameters for the `migrate checkpoint` command.
+	MigrateCheckpointParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+		DevURL    string
+
+		DirURL      string
+		DirFormat   DirFormat
+		Schema      []string
+		LockTimeout string
+		Name        string
+		Tag         string // Tag of the checkpoint, e.g. "v1.2.0".
+	}
+	// MigrateSetParams are the parameters for the `migrate set` command.
+	MigrateSetParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+
+		DirURL          string
+		DirFormat       DirFormat
+		URL             string
+		RevisionsSchema string
+		Version         string
+	}
+	// MigrateSet contains a summary of the changes made to the revisions table by 'migrate set'.
+	MigrateSet struct {
+		Added   []RevisionOp `json:"Added,omitempty"`   // Revisions added to the table.
+		Removed []RevisionOp `json:"Removed,omitempty"` // Revisions removed from the table.
+		Updated []RevisionOp `json:"Updated,omitempty"` // Revisions updated in the table.
+		Current string       `json:"Current,omitempty"` // Current version in the revisions table.
+	}
+	// RevisionOp describes a revision changed by 'migrate set'.
+	RevisionOp struct {
+		Version     string `json:"Version,omitempty"`
+		Description string `json:"Description,omitempty"`
+	}
+	// MigrateValidateParams are the parameters for the `migrate validate` command.
+	MigrateValidateParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+		DevURL    string
+
+		DirURL    string
+		DirFormat DirFormat
+	}
+	// MigrateValidateError is returned by MigrateValidate when the migration directory is invalid.
+	MigrateValidateError struct {
+		Failures []*MigrateValidateFailure
+		err      error // The underlying CLI error.
+	}
+	// MigrateValidateFailure describes a single failure found by 'migrate validate'.
+	MigrateValidateFailure struct {
+		Kind    ValidateFailureKind
+		Version string // Version of the failed file, if known.
+		Stmt    string // SQL statement that failed, if exists.
+		Text    string // Error message.
+	}
+	// ValidateFailureKind describes the cause of a MigrateValidateFailure.
+	ValidateFailureKind string
+	// MigrateLsParams are the parameters for the `migrate ls` command.
+	MigrateLsParams struct {
+		ConfigURL string
+		Env       string
+		Vars      VarArgs
+
+		DirURL    string
+		DirFormat DirFormat
+		Short     bool // List only the versions of the files.
+		Latest    bool // List only the latest file.
+	}
+)
+
+// Kinds of the failures found by 'migrate validate'.
+const (
+	ValidateChecksum  ValidateFailureKind = "checksum"  // The atlas.sum file does not match the directory.
+	ValidateStatement ValidateFailureKind = "statement" // A statement failed when replaying the directory.
 )
 
 // MigratePush runs the 'migrate push' command.
-func (c *Client) MigratePush(ctx context.Context, params *MigratePushParams) (string, error) {
+func (c *Client) MigratePush(ctx context.Context, params *MigratePushParams, opts ...ClientOption) (string, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return "", err
+	}
+	resp, err := stringVal(c.runCommand(ctx, args))
+	return strings.TrimSpace(resp), err
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigratePushParams) AsArgs() ([]string, error) {
 	args := []string{"migrate", "push"}
-	if params.DevURL != "" {
-		args = append(args, "--dev-url", params.DevURL)
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if params.DirFormat != "" {
-		args = append(args, "--dir-format", params.DirFormat)
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
 	}
-	if params.LockTimeout != "" {
-		args = append(args, "--lock-timeout", params.LockTimeout)
+	if p.LockTimeout != "" {
+		args = append(args, "--lock-timeout", p.LockTimeout)
 	}
-	if params.Context != nil {
-		buf, err := json.Marshal(params.Context)
+	if p.Context != nil {
+		buf, err := json.Marshal(p.Context)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		args = append(args, "--context", string(buf))
 	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
-	}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
 	}
-	if params.Name == "" {
-		return "", errors.New("directory name cannot be empty")
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	if params.Tag != "" {
-		args = append(args, fmt.Sprintf("%s:%s", params.Name, params.Tag))
+	if p.Tag != "" {
+		args = append(args, fmt.Sprintf("%s:%s", p.Name, p.Tag))
 	} else {
-		args = append(args, params.Name)
+		args = append(args, p.Name)
 	}
-	resp, err := stringVal(c.runCommand(ctx, args))
-	return strings.TrimSpace(resp), err
+	return args, nil
 }
 
 // MigrateApply runs the 'migrate apply' command.
-func (c *Client) MigrateApply(ctx context.Context, params *MigrateApplyParams) (*MigrateApply, error) {
-	return firstResult(c.MigrateApplySlice(ctx, params))
+func (c *Client) MigrateApply(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) (*MigrateApply, error) {
+	return firstResult(c.MigrateApplySlice(ctx, params, opts...))
 }
 
 // MigrateApplySlice runs the 'migrate apply' command for multiple targets.
-func (c *Client) MigrateApplySlice(ctx context.Context, params *MigrateApplyParams) ([]*MigrateApply, error) {
-	args := []string{"migrate", "apply", "--format", "{{ json . }}"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+func (c *Client) MigrateApplySlice(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) ([]*MigrateApply, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	return jsonDecodeErr(newMigrateApplyError)(c.runCommand(ctx, args))
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateApplyParams) AsArgs() ([]string, error) {
+	flags, err := p.flags()
+	if err != nil {
+		return nil, err
 	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+	return append([]string{"migrate", "apply", "--format", "{{ json . }}"}, flags...), nil
+}
+
+// flags returns the command-line flags of the 'migrate apply' command.
+func (p *MigrateApplyParams) flags() ([]string, error) {
+	var args []string
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
 	}
-	if params.Context != nil {
-		buf, err := json.Marshal(params.Context)
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.Context != nil {
+		buf, err := json.Marshal(p.Context)
 		if err != nil {
 			return nil, err
 		}
 		args = append(args, "--context", string(buf))
 	}
-	if params.URL != "" {
-		args = append(args, "--url", params.URL)
+	if p.URL != "" {
+		args = append(args, "--url", p.URL)
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if params.AllowDirty {
+	if p.AllowDirty {
 		args = append(args, "--allow-dirty")
 	}
-	if params.DryRun {
+	if p.DryRun {
 		args = append(args, "--dry-run")
 	}
-	if params.RevisionsSchema != "" {
-		args = append(args, "--revisions-schema", params.RevisionsSchema)
+	if p.RevisionsSchema != "" {
+		args = append(args, "--revisions-schema", p.RevisionsSchema)
 	}
-	if params.BaselineVersion != "" {
-		args = append(args, "--baseline", params.BaselineVersion)
+	if p.BaselineVersion != "" {
+		args = append(args, "--baseline", p.BaselineVersion)
 	}
-	if params.TxMode != "" {
-		args = append(args, "--tx-mode", params.TxMode)
+	if p.TxMode != "" {
+		args = append(args, "--tx-mode", string(p.TxMode))
 	}
-	if params.ExecOrder != "" {
-		args = append(args, "--exec-order", string(params.ExecOrder))
+	if p.ExecOrder != "" {
+		args = append(args, "--exec-order", string(p.ExecOrder))
 	}
-	if params.Amount > 0 {
-		args = append(args, strconv.FormatUint(params.Amount, 10))
+	if p.Amount > 0 {
+		args = append(args, strconv.FormatUint(p.Amount, 10))
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	return jsonDecodeErr(newMigrateApplyError)(c.runCommand(ctx, args))
+	return args, nil
 }
 
 // MigrateDown runs the 'migrate down' command.
-func (c *Client) MigrateDown(ctx context.Context, params *MigrateDownParams) (*MigrateDown, error) {
-	args := []string{"migrate", "down", "--format", "{{ json . }}"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+func (c *Client) MigrateDown(ctx context.Context, params *MigrateDownParams, opts ...ClientOption) (*MigrateDown, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.runCommand(ctx, args)
+	if cliErr := (&Error{}); errors.As(err, &cliErr) && cliErr.Stderr == "" {
+		r = strings.NewReader(cliErr.Stdout)
+		err = nil
+	}
+	// NOTE: This command only support one result.
+	return firstResult(jsonDecode[MigrateDown](r, err))
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateDownParams) AsArgs() ([]string, error) {
+	flags, err := p.flags()
+	if err != nil {
+		return nil, err
 	}
-	if params.DevURL != "" {
-		args = append(args, "--dev-url", params.DevURL)
+	return append([]string{"migrate", "down", "--format", "{{ json . }}"}, flags...), nil
+}
+
+// flags returns the command-line flags of the 'migrate down' command.
+func (p *MigrateDownParams) flags() ([]string, error) {
+	var args []string
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
 	}
-	if params.Context != nil {
-		buf, err := json.Marshal(params.Context)
+	if p.Context != nil {
+		buf, err := json.Marshal(p.Context)
 		if err != nil {
 			return nil, err
 		}
 		args = append(args, "--context", string(buf))
 	}
-	if params.URL != "" {
-		args = append(args, "--url", params.URL)
+	if p.URL != "" {
+		args = append(args, "--url", p.URL)
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
-	}
-	if params.RevisionsSchema != "" {
-		args = append(args, "--revisions-schema", params.RevisionsSchema)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if params.ToVersion != "" {
-		args = append(args, "--to-version", params.ToVersion)
+	if p.RevisionsSchema != "" {
+		args = append(args, "--revisions-schema", p.RevisionsSchema)
 	}
-	if params.ToTag != "" {
-		args = append(args, "--to-tag", params.ToTag)
+	if p.ToVersion != "" {
+		args = append(args, "--to-version", p.ToVersion)
 	}
-	if params.Amount > 0 {
-		args = append(args, strconv.FormatUint(params.Amount, 10))
+	if p.ToTag != "" {
+		args = append(args, "--to-tag", p.ToTag)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.Amount > 0 {
+		args = append(args, strconv.FormatUint(p.Amount, 10))
 	}
-	r, err := c.runCommand(ctx, args)
-	if cliErr := (&Error{}); errors.As(err, &cliErr) && cliErr.Stderr == "" {
-		r = strings.NewReader(cliErr.Stdout)
-		err = nil
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	// NOTE: This command only support one result.
-	return firstResult(jsonDecode[MigrateDown](r, err))
+	return args, nil
 }
 
 // MigrateTest runs the 'migrate test' command.
-func (c *Client) MigrateTest(ctx context.Context, params *MigrateTestParams) (string, error) {
+func (c *Client) MigrateTest(ctx context.Context, params *MigrateTestParams, opts ...ClientOption) (string, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return "", err
+	}
+	return stringVal(c.runCommand(ctx, args))
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateTestParams) AsArgs() ([]string, error) {
 	args := []string{"migrate", "test"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
 	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if params.DirFormat != "" {
-		args = append(args, "--dir-format", params.DirFormat)
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
 	}
-	if params.DevURL != "" {
-		args = append(args, "--dev-url", params.DevURL)
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
 	}
-	if params.Context != nil {
-		buf, err := json.Marshal(params.Context)
+	if p.Context != nil {
+		buf, err := json.Marshal(p.Context)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		args = append(args, "--context", string(buf))
 	}
-	if params.RevisionsSchema != "" {
-		args = append(args, "--revisions-schema", params.RevisionsSchema)
+	if p.RevisionsSchema != "" {
+		args = append(args, "--revisions-schema", p.RevisionsSchema)
 	}
-	if params.Run != "" {
-		args = append(args, "--run", params.Run)
+	if p.Run != "" {
+		args = append(args, "--run", p.Run)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	if len(params.Paths) > 0 {
-		args = append(args, params.Paths...)
+	if len(p.Paths) > 0 {
+		args = append(args, p.Paths...)
 	}
-	return stringVal(c.runCommand(ctx, args))
+	return args, nil
 }
 
-// MigrateStatus runs the 'migrate status' command.
-func (c *Client) MigrateStatus(ctx context.Context, params *MigrateStatusParams) (*MigrateStatus, error) {
-	args := []string{"migrate", "status", "--format", "{{ json . }}"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
-	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+// MigrateStatus runs the 'migrate status' command. The Checkpoint and Tag fields
+// of the returned files are read from disk after the command ran, and are set only
+// for local (file://) directories. Files of remote directories, like atlas://, are
+// never flagged as checkpoints.
+func (c *Client) MigrateStatus(ctx context.Context, params *MigrateStatusParams, opts ...ClientOption) (*MigrateStatus, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	if params.URL != "" {
-		args = append(args, "--url", params.URL)
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	st, err := firstResult(jsonDecode[MigrateStatus](c.runCommand(ctx, args)))
+	if err != nil {
+		return nil, err
 	}
-	if params.RevisionsSchema != "" {
-		args = append(args, "--revisions-schema", params.RevisionsSchema)
+	// Checkpoint files are flagged by reading local directories. The directory
+	// reported by the CLI is preferred, as it is the one resolved from the config.
+	dirURL := params.DirURL
+	if st.Env.Dir != "" {
+		dirURL = st.Env.Dir
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if dir, ok := c.localDir(dirURL, params.ConfigURL, params.Env); ok {
+		if err := markCheckpoints(dir, st.Available, st.Pending); err != nil {
+			return nil, err
+		}
 	}
-	// NOTE: This command only support one result.
-	return firstResult(jsonDecode[MigrateStatus](c.runCommand(ctx, args)))
+	return st, nil
 }
 
-// MigrateDiff runs the 'migrate diff --dry-run' command and returns the generated migration files without changing the filesystem.
-// Requires atlas CLI to be logged in to the cloud.
-func (c *Client) MigrateDiff(ctx context.Context, params *MigrateDiffParams) (*MigrateDiff, error) {
-	args := []string{"migrate", "diff", "--dry-run"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
-	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
-	}
-	if params.ToURL != "" {
-		args = append(args, "--to", params.ToURL)
-	}
-	if params.DevURL != "" {
-		args = append(args, "--dev-url", params.DevURL)
+// markCheckpoints flags the checkpoint files of the given directory.
+func markCheckpoints(dir string, files ...[]File) error {
+	for _, list := range files {
+		for i := range list {
+			if list[i].Checkpoint || list[i].Name == "" {
+				continue
+			}
+			buf, err := os.ReadFile(filepath.Join(dir, list[i].Name))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			f := newFile(list[i].Name, buf)
+			list[i].Checkpoint, list[i].Tag = f.Checkpoint, f.Tag
+		}
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	return nil
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateStatusParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "status", "--format", "{{ json . }}"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
 	}
-	if params.DirFormat != "" {
-		args = append(args, "--dir-format", params.DirFormat)
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
 	}
-	if params.LockTimeout != "" {
-		args = append(args, "--lock-timeout", params.LockTimeout)
+	if p.URL != "" {
+		args = append(args, "--url", p.URL)
 	}
-	if params.Qualifier != "" {
-		args = append(args, "--qualifier", params.Qualifier)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if len(params.Schema) > 0 {
-		args = append(args, "--schema", strings.Join(params.Schema, ","))
+	if p.RevisionsSchema != "" {
+		args = append(args, "--revisions-schema", p.RevisionsSchema)
 	}
-	if params.Format != "" {
-		args = append(args, "--format", params.Format)
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	// NOTE: This command only support one result.
+	return args, nil
+}
+
+// MigrateDiff runs the 'migrate diff --dry-run' command and returns the generated migration files without changing the filesystem.
+// Requires atlas CLI to be logged in to the cloud.
+func (c *Client) MigrateDiff(ctx context.Context, params *MigrateDiffParams, opts ...ClientOption) (*MigrateDiff, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	if params.Name != "" {
-		args = append(args, params.Name)
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
 	}
 	v, err := jsonDecode[MigrateDiff](c.runCommand(ctx, args))
 	var e *Error
@@ -456,8 +666,55 @@ func (c *Client) MigrateDiff(ctx context.Context, params *MigrateDiffParams) (*M
 	return firstResult(v, nil)
 }
 
+// AsArgs returns the parameters as arguments.
+func (p *MigrateDiffParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "diff", "--dry-run"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.ToURL != "" {
+		args = append(args, "--to", p.ToURL)
+	}
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if p.LockTimeout != "" {
+		args = append(args, "--lock-timeout", p.LockTimeout)
+	}
+	if p.Qualifier != "" {
+		args = append(args, "--qualifier", p.Qualifier)
+	}
+	if len(p.Schema) > 0 {
+		args = append(args, "--schema", strings.Join(p.Schema, ","))
+	}
+	if p.Format != "" {
+		args = append(args, "--format", p.Format)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.Name != "" {
+		args = append(args, p.Name)
+	}
+	return args, nil
+}
+
 // MigrateLint runs the 'migrate lint' command.
-func (c *Client) MigrateLint(ctx context.Context, params *MigrateLintParams) (*SummaryReport, error) {
+func (c *Client) MigrateLint(ctx context.Context, params *MigrateLintParams, opts ...ClientOption) (*SummaryReport, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 	if params.Writer != nil || params.Web {
 		return nil, errors.New("atlasexec: Writer or Web reporting are not supported with MigrateLint, use MigrateLintError")
 	}
@@ -475,51 +732,614 @@ func (c *Client) MigrateLint(ctx context.Context, params *MigrateLintParams) (*S
 }
 
 // MigrateHash runs the 'migrate hash' command.
-func (c *Client) MigrateHash(ctx context.Context, params *MigrateHashParams) error {
-	args := []string{"migrate", "hash"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
-	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
-	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
-	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+func (c *Client) MigrateHash(ctx context.Context, params *MigrateHashParams, opts ...ClientOption) error {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return err
 	}
-	if params.DirFormat != "" {
-		args = append(args, "--dir-format", params.DirFormat)
+	args, err := params.AsArgs()
+	if err != nil {
+		return err
 	}
-	_, err := c.runCommand(ctx, args)
+	_, err = c.runCommand(ctx, args)
 	return err
 }
 
-// MigrateRebase runs the 'migrate rebase' command.
-func (c *Client) MigrateRebase(ctx context.Context, params *MigrateRebaseParams) error {
-	args := []string{"migrate", "rebase"}
-	if params.Env != "" {
-		args = append(args, "--env", params.Env)
+// AsArgs returns the parameters as arguments.
+func (p *MigrateHashParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "hash"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
 	}
-	if params.ConfigURL != "" {
-		args = append(args, "--config", params.ConfigURL)
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
 	}
-	if params.Vars != nil {
-		args = append(args, params.Vars.AsArgs()...)
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
 	}
-	if params.DirURL != "" {
-		args = append(args, "--dir", params.DirURL)
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
 	}
-	args = append(args, strings.Join(params.Files, " "))
-	_, err := c.runCommand(ctx, args)
-	return err
+	return args, nil
 }
 
-// MigrateLintError runs the 'migrate lint' command, the output is written to params.Writer and reports
+// MigrateRebase runs the 'migrate rebase' command.
+func (c *Client) MigrateRebase(ctx context.Context, params *MigrateRebaseParams, opts ...ClientOption) error {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return err
+	}
+	_, err = c.runCommand(ctx, args)
+	return err
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateRebaseParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "rebase"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	args = append(args, strings.Join(p.Files, " "))
+	return args, nil
+}
+
+// MigrateNew runs the 'migrate new' command and returns the created file. If
+// params.Content is set, it is written to the file and the directory is re-hashed.
+//
+// The file is located only for local directories, i.e. if DirURL uses the file
+// scheme, or if it is empty and no project file is used. Otherwise, a nil file is
+// returned, and setting Content is an error. Directory formats that create more than
+// one file, like golang-migrate and flyway, are reported as an error, as the created
+// file is ambiguous. Use the atlas-cli directly for these formats.
+func (c *Client) MigrateNew(ctx context.Context, params *MigrateNewParams, opts ...ClientOption) (*File, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := c.localDir(params.DirURL, params.ConfigURL, params.Env)
+	if !ok && params.Content != "" {
+		return nil, errors.New("atlasexec: migrate new: Content requires a local migration directory")
+	}
+	var before map[string]string
+	if ok {
+		if before, err = sqlFiles(dir); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.runCommand(ctx, args); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	files, err := changedFiles(dir, before)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("atlasexec: migrate new: no file was created in %q", dir)
+	}
+	if len(files) > 1 {
+		names := make([]string, len(files))
+		for i, f := range files {
+			names[i] = f.Name
+		}
+		return nil, fmt.Errorf("atlasexec: migrate new: expected one created file in %q, got: %s", dir, strings.Join(names, ", "))
+	}
+	name := files[0].Name
+	if params.Content != "" {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(params.Content), 0644); err != nil {
+			return nil, err
+		}
+		err := c.MigrateHash(ctx, &MigrateHashParams{
+			ConfigURL: params.ConfigURL,
+			Env:       params.Env,
+			Vars:      params.Vars,
+			DirURL:    params.DirURL,
+			DirFormat: params.DirFormat,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return newFile(name, buf), nil
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateNewParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "new"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if p.Name != "" {
+		args = append(args, p.Name)
+	}
+	return args, nil
+}
+
+// localDir returns the path of the given migration directory, if it is a local one.
+func (c *Client) localDir(dirURL, configURL, env string) (string, bool) {
+	if dirURL == "" {
+		if configURL != "" || env != "" {
+			return "", false
+		}
+		dirURL = "file://migrations"
+	}
+	u, err := url.Parse(dirURL)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	dir := filepath.FromSlash(u.Host + u.Path)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(c.workingDir, dir)
+	}
+	return dir, true
+}
+
+// sqlFiles returns the content of the SQL files in the given directory, keyed by their names.
+func sqlFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name()] = string(buf)
+	}
+	return files, nil
+}
+
+// changedFiles returns the SQL files in the given directory that were added
+// or changed since the before snapshot was taken, sorted by their names.
+func changedFiles(dir string, before map[string]string) ([]*File, error) {
+	after, err := sqlFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*File
+	for name, content := range after {
+		if prev, ok := before[name]; !ok || prev != content {
+			files = append(files, newFile(name, []byte(content)))
+		}
+	}
+	slices.SortFunc(files, func(a, b *File) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return files, nil
+}
+
+// newFile returns the File with the given name and content.
+func newFile(name string, buf []byte) *File {
+	f := migrate.NewLocalFile(name, buf)
+	tag, err := f.CheckpointTag()
+	return &File{
+		Name:        name,
+		Version:     f.Version(),
+		Description: f.Desc(),
+		Content:     string(buf),
+		Checkpoint:  err == nil,
+		Tag:         tag,
+	}
+}
+
+// MigrateImport runs the 'migrate import' command, and returns the files written
+// to the target directory. Like MigrateNew, the files are returned only for local
+// directories.
+//
+//	files, err := c.MigrateImport(ctx, &atlasexec.MigrateImportParams{
+//		FromFS:     gooseMigrations, // embed.FS
+//		FromFormat: atlasexec.DirFormatGoose,
+//		ToURL:      "file://migrations",
+//	})
+func (c *Client) MigrateImport(ctx context.Context, params *MigrateImportParams, opts ...ClientOption) ([]*File, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.FromFS != nil {
+		wd, err := NewWorkingDir()
+		if err != nil {
+			return nil, err
+		}
+		defer wd.Close()
+		if err := copyFS(wd, params.FromFS); err != nil {
+			return nil, err
+		}
+		staged := *params
+		staged.FromFS, staged.FromURL = nil, "file://"+filepath.ToSlash(wd.Path())
+		params = &staged
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := c.localDir(params.ToURL, params.ConfigURL, params.Env)
+	var before map[string]string
+	if ok {
+		if before, err = sqlFiles(dir); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.runCommand(ctx, args); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return changedFiles(dir, before)
+}
+
+// copyFS copies all files of src to the working directory as-is. Unlike WorkingDir.CopyFS,
+// it does not read migrate.Dir implementations using their Files method, which skips
+// files of other migration tools, e.g. the down migrations of golang-migrate.
+func copyFS(wd *WorkingDir, src fs.FS) error {
+	// Directories that cannot be walked, like migrate.MemDir,
+	// are copied using their Files method by WorkingDir.CopyFS.
+	if _, ok := src.(migrate.Dir); ok {
+		if _, err := fs.Stat(src, "."); err != nil {
+			return wd.CopyFS("", src)
+		}
+	}
+	dst := wd.Path()
+	return fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == "." {
+			return err
+		}
+		name := filepath.Join(dst, path)
+		if d.IsDir() {
+			return os.Mkdir(name, 0700)
+		}
+		data, err := fs.ReadFile(src, path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(name, data, 0644)
+	})
+}
+
+// AsArgs returns the parameters as arguments. Note, the FromFS
+// parameter is staged only by MigrateImport, use FromURL instead.
+func (p *MigrateImportParams) AsArgs() ([]string, error) {
+	if p.FromFS != nil {
+		return nil, errors.New("atlasexec: migrate import: FromFS is supported only by MigrateImport, use FromURL")
+	}
+	args := []string{"migrate", "import"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.FromURL != "" {
+		from := p.FromURL
+		if p.FromFormat != "" {
+			u, err := url.Parse(from)
+			if err != nil {
+				return nil, fmt.Errorf("atlasexec: parsing FromURL: %w", err)
+			}
+			q := u.Query()
+			q.Set("format", string(p.FromFormat))
+			u.RawQuery = q.Encode()
+			from = u.String()
+		}
+		args = append(args, "--from", from)
+	}
+	if p.ToURL != "" {
+		args = append(args, "--to", p.ToURL)
+	}
+	return args, nil
+}
+
+// MigrateCheckpoint runs the 'migrate checkpoint' command and returns the created
+// checkpoint file. If params.Tag is set, the checkpoint is tagged and the directory
+// is re-hashed. Like MigrateNew, the file is returned only for local directories.
+func (c *Client) MigrateCheckpoint(ctx context.Context, params *MigrateCheckpointParams, opts ...ClientOption) (*File, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := c.localDir(params.DirURL, params.ConfigURL, params.Env)
+	if !ok && params.Tag != "" {
+		return nil, errors.New("atlasexec: migrate checkpoint: Tag requires a local migration directory")
+	}
+	var before map[string]string
+	if ok {
+		if before, err = sqlFiles(dir); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.runCommand(ctx, args); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	files, err := changedFiles(dir, before)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(files, func(f *File) bool { return f.Checkpoint })
+	if i == -1 {
+		return nil, fmt.Errorf("atlasexec: migrate checkpoint: no checkpoint file was created in %q", dir)
+	}
+	f := files[i]
+	if params.Tag != "" {
+		content := reCheckpoint.ReplaceAllLiteralString(f.Content, "-- atlas:checkpoint "+params.Tag)
+		if err := os.WriteFile(filepath.Join(dir, f.Name), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+		err := c.MigrateHash(ctx, &MigrateHashParams{
+			ConfigURL: params.ConfigURL,
+			Env:       params.Env,
+			Vars:      params.Vars,
+			DirURL:    params.DirURL,
+			DirFormat: params.DirFormat,
+		})
+		if err != nil {
+			return nil, err
+		}
+		f = newFile(f.Name, []byte(content))
+	}
+	return f, nil
+}
+
+// reCheckpoint matches the checkpoint directive of a migration file.
+var reCheckpoint = regexp.MustCompile(`(?m)^-- atlas:checkpoint\b.*$`)
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateCheckpointParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "checkpoint"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if len(p.Schema) > 0 {
+		args = append(args, "--schema", strings.Join(p.Schema, ","))
+	}
+	if p.LockTimeout != "" {
+		args = append(args, "--lock-timeout", p.LockTimeout)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.Name != "" {
+		args = append(args, p.Name)
+	}
+	return args, nil
+}
+
+// MigrateSet runs the 'migrate set' command.
+func (c *Client) MigrateSet(ctx context.Context, params *MigrateSetParams, opts ...ClientOption) (*MigrateSet, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: This command only support one result.
+	return firstResult(jsonDecode[MigrateSet](c.runCommand(ctx, args)))
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateSetParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "set", "--format", "{{ json . }}"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.URL != "" {
+		args = append(args, "--url", p.URL)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if p.RevisionsSchema != "" {
+		args = append(args, "--revisions-schema", p.RevisionsSchema)
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	if p.Version != "" {
+		args = append(args, p.Version)
+	}
+	return args, nil
+}
+
+// MigrateValidate runs the 'migrate validate' command. If the migration directory
+// is invalid, a MigrateValidateError describing the failures is returned.
+func (c *Client) MigrateValidate(ctx context.Context, params *MigrateValidateParams, opts ...ClientOption) error {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return err
+	}
+	_, err = c.runCommand(ctx, args)
+	if cliErr := (&Error{}); errors.As(err, &cliErr) {
+		if fs := validateFailures(cliErr.Stderr); len(fs) > 0 {
+			return replaceLast(err, &MigrateValidateError{Failures: fs, err: cliErr})
+		}
+	}
+	return err
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateValidateParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "validate"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.DevURL != "" {
+		args = append(args, "--dev-url", p.DevURL)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	return args, nil
+}
+
+// The format of migrate.ExecutionError, e.g. `executing statement "..." from version "1": error`.
+var reStmtErr = regexp.MustCompile(`executing statement ("(?:[^"\\]|\\.)*") from version ("(?:[^"\\]|\\.)*"): ([^\n]*)`)
+
+// validateFailures extracts the failures reported by 'migrate validate' from its stderr.
+func validateFailures(stderr string) (fs []*MigrateValidateFailure) {
+	if strings.Contains(stderr, migrate.ErrChecksumMismatch.Error()) {
+		fs = append(fs, &MigrateValidateFailure{Kind: ValidateChecksum, Text: migrate.ErrChecksumMismatch.Error()})
+	}
+	for _, m := range reStmtErr.FindAllStringSubmatch(stderr, -1) {
+		stmt, err1 := strconv.Unquote(m[1])
+		ver, err2 := strconv.Unquote(m[2])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		fs = append(fs, &MigrateValidateFailure{Kind: ValidateStatement, Version: ver, Stmt: stmt, Text: m[3]})
+	}
+	return fs
+}
+
+// MigrateLs runs the 'migrate ls' command and returns the listed files.
+func (c *Client) MigrateLs(ctx context.Context, params *MigrateLsParams, opts ...ClientOption) ([]string, error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	s, err := stringVal(c.runCommand(ctx, args))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(s), nil
+}
+
+// AsArgs returns the parameters as arguments.
+func (p *MigrateLsParams) AsArgs() ([]string, error) {
+	args := []string{"migrate", "ls"}
+	if p.Env != "" {
+		args = append(args, "--env", p.Env)
+	}
+	if p.ConfigURL != "" {
+		args = append(args, "--config", p.ConfigURL)
+	}
+	if p.DirURL != "" {
+		args = append(args, "--dir", p.DirURL)
+	}
+	if p.DirFormat != "" {
+		args = append(args, "--dir-format", string(p.DirFormat))
+	}
+	if p.Short {
+		args = append(args, "--short")
+	}
+	if p.Latest {
+		args = append(args, "--latest")
+	}
+	if p.Vars != nil {
+		args = append(args, p.Vars.AsArgs()...)
+	}
+	return args, nil
+}
+
+// MigrateLintError runs the 'migrate lint' command, the output is written to params.Writer and reports
 // if an error occurred. If the error is a setup error, a Error is returned. If the error is a lint error,
 // LintErr is returned.
-func (c *Client) MigrateLintError(ctx context.Context, params *MigrateLintParams) error {
+func (c *Client) MigrateLintError(ctx context.Context, params *MigrateLintParams, opts ...ClientOption) error {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return err
+	}
 	args, err := params.AsArgs()
 	if err != nil {
 		return err
@@ -584,11 +1404,11 @@ func (p *MigrateLintParams) AsArgs() ([]string, error) {
 	if p.Vars != nil {
 		args = append(args, p.Vars.AsArgs()...)
 	}
-	format := "{{ json . }}"
+	format := LintFormatJSON
 	if p.Format != "" {
 		format = p.Format
 	}
-	args = append(args, "--format", format)
+	args = append(args, "--format", string(format))
 	return args, nil
 }
 
@@ -707,16 +1527,28 @@ func (r MigrateStatus) Amount(version string) (amount uint64, ok bool) {
 	return amount, false
 }
 
-func newMigrateApplyError(r []*MigrateApply, stderr string) error {
-	return &MigrateApplyError{Result: r, Stderr: stderr}
+func newMigrateApplyError(r []*MigrateApply, err *Error) error {
+	return &MigrateApplyError{Result: r, Stderr: err.Stderr, err: err}
 }
 
 // Error implements the error interface.
 func (e *MigrateApplyError) Error() string {
 	if e.Stderr != "" {
-		return e.Stderr
+		return redactErr(e.err, e.Stderr)
+	}
+	return redactErr(e.err, last(e.Result).Error)
+}
+
+// Error implements the error interface.
+func (e *MigrateValidateError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		msgs[i] = f.Text
+		if f.Version != "" {
+			msgs[i] = fmt.Sprintf("version %q: %s", f.Version, f.Text)
+		}
 	}
-	return last(e.Result).Error
+	return redactErr(e.err, "migration directory is invalid: "+strings.Join(msgs, "; "))
 }
 
 func plural(n int) (s string) {
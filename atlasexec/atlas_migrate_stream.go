@@ -0,0 +1,271 @@
+package atlasexec
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// MigrateEvent is a progress event of a 'migrate apply' or 'migrate down' execution.
+	MigrateEvent struct {
+		Type MigrateEventType
+		// Version of the migration file the event belongs to. For
+		// MigrateEventPlan, it holds the target version of the database.
+		Version string
+		// Total number of pending migration files, set for MigrateEventPlan.
+		Total int
+		// Stmt holds the statement of MigrateEventStmt and MigrateEventCheck.
+		Stmt string
+		// Duration of the migration file for MigrateEventFileDone,
+		// or of the whole execution for MigrateEventDone.
+		Duration time.Duration
+		// Error message of MigrateEventError.
+		Error string
+		// Line holds the log line of MigrateEventUnknown.
+		Line string
+	}
+	// MigrateEventType is the type of a MigrateEvent.
+	MigrateEventType string
+)
+
+// MigrateEventType values.
+const (
+	MigrateEventPlan      MigrateEventType = "plan"       // The migration plan was computed.
+	MigrateEventFileStart MigrateEventType = "file_start" // A migration file started executing.
+	MigrateEventStmt      MigrateEventType = "stmt"       // A statement was executed.
+	MigrateEventCheck     MigrateEventType = "check"      // A pre-migration check was executed.
+	MigrateEventFileDone  MigrateEventType = "file_done"  // A migration file was executed successfully.
+	MigrateEventError     MigrateEventType = "error"      // The execution failed.
+	MigrateEventDone      MigrateEventType = "done"       // The execution completed.
+	MigrateEventUnknown   MigrateEventType = "unknown"    // A log line that was not recognized.
+)
+
+// MigrateApplyStream runs the 'migrate apply' command and streams its progress. Unlike
+// MigrateApply, events are delivered while the command is running. The error of the
+// command is reported by the Err method of the stream once all events were consumed.
+//
+// Events are parsed from the text log of the atlas-cli. Lines that are not recognized,
+// for example, after the log format of the CLI was changed, are reported as events of
+// type MigrateEventUnknown instead of being dropped.
+//
+//	s, err := c.MigrateApplyStream(ctx, &atlasexec.MigrateApplyParams{Env: "prod"})
+//	if err != nil {
+//		return err
+//	}
+//	for s.Next() {
+//		e, _ := s.Current()
+//		log.Println(e.Type, e.Version)
+//	}
+//	return s.Err()
+func (c *Client) MigrateApplyStream(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) (Stream[*MigrateEvent], error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	flags, err := params.flags()
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.runCommandStream(ctx, append([]string{"migrate", "apply"}, flags...))
+	if err != nil {
+		return nil, err
+	}
+	return &migrateEventStream{s: s}, nil
+}
+
+// MigrateDownStream runs the 'migrate down' command and streams its progress.
+// See MigrateApplyStream for more details.
+func (c *Client) MigrateDownStream(ctx context.Context, params *MigrateDownParams, opts ...ClientOption) (Stream[*MigrateEvent], error) {
+	c = c.With(opts...)
+	params = applyDefaults(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	flags, err := params.flags()
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.runCommandStream(ctx, append([]string{"migrate", "down"}, flags...))
+	if err != nil {
+		return nil, err
+	}
+	return &migrateEventStream{s: s}, nil
+}
+
+type migrateEventStream struct {
+	s     Stream[string]
+	p     migrateLogParser
+	queue []*MigrateEvent
+	cur   *MigrateEvent
+	eof   bool
+}
+
+// Next advances the stream to the next MigrateEvent.
+func (s *migrateEventStream) Next() bool {
+	s.cur = nil
+	for len(s.queue) == 0 {
+		if s.eof {
+			return false
+		}
+		if s.s.Next() {
+			if line, err := s.s.Current(); err == nil {
+				s.queue = s.p.feed(line)
+				continue
+			}
+		}
+		// The command exited or failed. Report the pending statement, if any.
+		s.eof = true
+		if e := s.p.flush(); e != nil {
+			s.queue = append(s.queue, e)
+		}
+	}
+	s.cur, s.queue = s.queue[0], s.queue[1:]
+	return true
+}
+
+// Current returns the current MigrateEvent from the stream.
+func (s *migrateEventStream) Current() (*MigrateEvent, error) {
+	if s.cur == nil {
+		return nil, s.s.Err()
+	}
+	return s.cur, nil
+}
+
+// Err returns the error of the command, if any.
+func (s *migrateEventStream) Err() error {
+	return s.s.Err()
+}
+
+// Close stops the command if it is still running and returns its final error.
+func (s *migrateEventStream) Close() error {
+	return s.s.Close()
+}
+
+var _ Stream[*MigrateEvent] = (*migrateEventStream)(nil)
+
+var (
+	reLogTarget    = regexp.MustCompile(`\bto (?:version )?(\S+)`)
+	reLogTotal     = regexp.MustCompile(`\((\d+) migrations? in total\)`)
+	reLogFileStart = regexp.MustCompile(`^-- (?:migrating|reverting) version (\S+)$`)
+	reLogChecks    = regexp.MustCompile(`^-- checks before (?:migrating|reverting) version (\S+)$`)
+	reLogOK        = regexp.MustCompile(`^-- ok \((.+)\)$`)
+	reLogDuration  = regexp.MustCompile(`^-- (\S+)$`)
+	reLogSeparator = regexp.MustCompile(`^-{5,}$`)
+)
+
+// migrateLogParser parses the text log of 'migrate apply' and 'migrate down':
+//
+//	Migrating to version 2 (2 migrations in total):
+//
+//	  -- checks before migrating version 1
+//	    -> SELECT true
+//	  -- ok (1ms)
+//
+//	  -- migrating version 1
+//	    -> CREATE TABLE t1 (
+//	         c int
+//	       );
+//	  -- ok (2.5ms)
+//
+//	  -------------------------
+//	  -- 3.5ms
+//	  -- 1 migration
+//	  -- 1 sql statement
+type migrateLogParser struct {
+	version string        // Version of the current file.
+	checks  bool          // Inside a checks block.
+	summary bool          // Inside the summary section.
+	pending *MigrateEvent // Statement that might span multiple lines.
+	indent  int           // Indentation of the pending statement.
+}
+
+// feed parses the given line and returns the events it completed.
+// Statements are reported once their following line is read, as
+// they may span multiple lines.
+func (p *migrateLogParser) feed(line string) []*MigrateEvent {
+	text := strings.TrimSpace(line)
+	if text == "" {
+		return nil
+	}
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	if p.pending != nil && indent > p.indent && !strings.HasPrefix(text, "-") {
+		p.pending.Stmt += "\n" + text
+		return nil
+	}
+	var events []*MigrateEvent
+	if e := p.flush(); e != nil {
+		events = append(events, e)
+	}
+	if e := p.parse(text, indent); e != nil {
+		events = append(events, e)
+	}
+	return events
+}
+
+// flush returns the pending statement, if exists.
+func (p *migrateLogParser) flush() *MigrateEvent {
+	e := p.pending
+	p.pending = nil
+	return e
+}
+
+// parse parses a single line. Statements are kept pending, and
+// lines that are not recognized are reported as unknown events.
+func (p *migrateLogParser) parse(text string, indent int) *MigrateEvent {
+	if p.summary && strings.HasPrefix(text, "-- ") {
+		// The first line of the summary holds the total duration,
+		// and the rest hold the number of files and statements.
+		if m := reLogDuration.FindStringSubmatch(text); m != nil {
+			if d, err := time.ParseDuration(m[1]); err == nil {
+				return &MigrateEvent{Type: MigrateEventDone, Duration: d}
+			}
+		}
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(text, "Migrating "):
+		e := &MigrateEvent{Type: MigrateEventPlan}
+		if m := reLogTarget.FindStringSubmatch(text); m != nil {
+			e.Version = m[1]
+		}
+		if m := reLogTotal.FindStringSubmatch(text); m != nil {
+			e.Total, _ = strconv.Atoi(m[1])
+		}
+		return e
+	case strings.HasPrefix(text, "-> "):
+		t := MigrateEventStmt
+		if p.checks {
+			t = MigrateEventCheck
+		}
+		p.pending, p.indent = &MigrateEvent{Type: t, Version: p.version, Stmt: strings.TrimPrefix(text, "-> ")}, indent
+	case strings.HasPrefix(text, "Error: "):
+		return &MigrateEvent{Type: MigrateEventError, Version: p.version, Error: strings.TrimPrefix(text, "Error: ")}
+	case text == "No migration files to execute":
+		return &MigrateEvent{Type: MigrateEventDone}
+	case reLogSeparator.MatchString(text):
+		p.summary = true
+	default:
+		if m := reLogFileStart.FindStringSubmatch(text); m != nil {
+			p.version, p.checks = m[1], false
+			return &MigrateEvent{Type: MigrateEventFileStart, Version: m[1]}
+		}
+		if m := reLogChecks.FindStringSubmatch(text); m != nil {
+			p.version, p.checks = m[1], true
+			return nil
+		}
+		if m := reLogOK.FindStringSubmatch(text); m != nil {
+			if p.checks {
+				p.checks = false
+				return nil
+			}
+			d, _ := time.ParseDuration(m[1])
+			return &MigrateEvent{Type: MigrateEventFileDone, Version: p.version, Duration: d}
+		}
+		return &MigrateEvent{Type: MigrateEventUnknown, Version: p.version, Line: text}
+	}
+	return nil
+}
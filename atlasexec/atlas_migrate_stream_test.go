@@ -0,0 +1,127 @@
+package atlasexec_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateApplyStream(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--url", "sqlite://file?mode=memory", "--dir", "file://testdata/migrations").
+		Stdout(readLog(t, "migrate_apply.log"))
+	s, err := c.MigrateApplyStream(context.Background(), &atlasexec.MigrateApplyParams{
+		URL:    "sqlite://file?mode=memory",
+		DirURL: "file://testdata/migrations",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*atlasexec.MigrateEvent{
+		{Type: atlasexec.MigrateEventPlan, Version: "20230926085734", Total: 3},
+		{Type: atlasexec.MigrateEventFileStart, Version: "20230727105553"},
+		{Type: atlasexec.MigrateEventStmt, Version: "20230727105553", Stmt: "CREATE TABLE t1 ( c1 int );"},
+		{Type: atlasexec.MigrateEventFileDone, Version: "20230727105553", Duration: 1054 * time.Microsecond},
+		{Type: atlasexec.MigrateEventFileStart, Version: "20230727105615"},
+		{Type: atlasexec.MigrateEventStmt, Version: "20230727105615", Stmt: "CREATE TABLE t2 (\nc1 int,\nc2 text\n);"},
+		{Type: atlasexec.MigrateEventFileDone, Version: "20230727105615", Duration: 612500 * time.Nanosecond},
+		{Type: atlasexec.MigrateEventCheck, Version: "20230926085734", Stmt: "SELECT NOT EXISTS (SELECT 1 FROM t2);"},
+		{Type: atlasexec.MigrateEventFileStart, Version: "20230926085734"},
+		{Type: atlasexec.MigrateEventStmt, Version: "20230926085734", Stmt: "DROP TABLE t2;"},
+		{Type: atlasexec.MigrateEventFileDone, Version: "20230926085734", Duration: 431375 * time.Nanosecond},
+		{Type: atlasexec.MigrateEventDone, Duration: 2317 * time.Microsecond},
+	}, collectEvents(t, s))
+	require.NoError(t, s.Err())
+}
+
+func TestMigrateApplyStream_Error(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--env", "prod").
+		Stdout(readLog(t, "migrate_apply_error.log")).
+		Stderr("Error: sql/migrate: executing statement \"CREATE TABLE t1 ( c1 int );\" from version \"20230727105615\": table `t1` already exists").
+		ExitCode(1)
+	s, err := c.MigrateApplyStream(context.Background(), &atlasexec.MigrateApplyParams{Env: "prod"})
+	require.NoError(t, err)
+	require.Equal(t, []*atlasexec.MigrateEvent{
+		{Type: atlasexec.MigrateEventPlan, Version: "20230727105615", Total: 1},
+		{Type: atlasexec.MigrateEventFileStart, Version: "20230727105615"},
+		{Type: atlasexec.MigrateEventStmt, Version: "20230727105615", Stmt: "CREATE TABLE t1 ( c1 int );"},
+		{Type: atlasexec.MigrateEventError, Version: "20230727105615", Error: "table `t1` already exists"},
+		{Type: atlasexec.MigrateEventDone, Duration: 1043 * time.Microsecond},
+	}, collectEvents(t, s))
+	var cliErr *atlasexec.Error
+	require.ErrorAs(t, s.Err(), &cliErr)
+	require.Contains(t, cliErr.Stderr, "already exists")
+}
+
+func TestMigrateApplyStream_ErrorLastLine(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--env", "prod").
+		Stdout("Migrating to version 1 (1 migrations in total):\n\n  -- migrating version 1\n    -> CREATE TABLE t1 (c int);\n    Error: table `t1` already exists\n").
+		ExitCode(1)
+	// The last line is delivered even if the command exited before it was consumed.
+	for range 50 {
+		s, err := c.MigrateApplyStream(context.Background(), &atlasexec.MigrateApplyParams{Env: "prod"})
+		require.NoError(t, err)
+		events := collectEvents(t, s)
+		require.Len(t, events, 4)
+		require.Equal(t, &atlasexec.MigrateEvent{Type: atlasexec.MigrateEventError, Version: "1", Error: "table `t1` already exists"}, events[3])
+		require.Error(t, s.Err())
+	}
+}
+
+func TestMigrateApplyStream_Unknown(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--env", "prod").Stdout(`Migrating to version 1 (1 migrations in total):
+
+  -- applying version 1
+    -> CREATE TABLE t1 (c int);
+  -- ok (2ms)
+`)
+	s, err := c.MigrateApplyStream(context.Background(), &atlasexec.MigrateApplyParams{Env: "prod"})
+	require.NoError(t, err)
+	require.Equal(t, []*atlasexec.MigrateEvent{
+		{Type: atlasexec.MigrateEventPlan, Version: "1", Total: 1},
+		{Type: atlasexec.MigrateEventUnknown, Line: "-- applying version 1"},
+		{Type: atlasexec.MigrateEventStmt, Stmt: "CREATE TABLE t1 (c int);"},
+		{Type: atlasexec.MigrateEventFileDone, Duration: 2 * time.Millisecond},
+	}, collectEvents(t, s))
+	require.NoError(t, s.Err())
+}
+
+func TestMigrateDownStream(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "down", "--env", "dev", "1").Stdout(readLog(t, "migrate_down.log"))
+	s, err := c.MigrateDownStream(context.Background(), &atlasexec.MigrateDownParams{Env: "dev", Amount: 1})
+	require.NoError(t, err)
+	require.Equal(t, []*atlasexec.MigrateEvent{
+		{Type: atlasexec.MigrateEventPlan, Version: "20230727105615", Total: 1},
+		{Type: atlasexec.MigrateEventCheck, Version: "20230926085734", Stmt: "SELECT NOT EXISTS (SELECT 1 FROM t1);"},
+		{Type: atlasexec.MigrateEventFileStart, Version: "20230926085734"},
+		{Type: atlasexec.MigrateEventStmt, Version: "20230926085734", Stmt: "CREATE TABLE `t2` (\n`c1` int NULL,\n`c2` text NULL\n);"},
+		{Type: atlasexec.MigrateEventFileDone, Version: "20230926085734", Duration: 1200 * time.Microsecond},
+		{Type: atlasexec.MigrateEventDone, Duration: 1500 * time.Microsecond},
+	}, collectEvents(t, s))
+	require.NoError(t, s.Err())
+}
+
+// readLog reads a log fixture of the atlas-cli from testdata/logs.
+func readLog(t *testing.T, name string) string {
+	b, err := os.ReadFile(filepath.Join("testdata", "logs", name))
+	require.NoError(t, err)
+	return string(b)
+}
+
+func collectEvents(t *testing.T, s atlasexec.Stream[*atlasexec.MigrateEvent]) []*atlasexec.MigrateEvent {
+	var events []*atlasexec.MigrateEvent
+	for s.Next() {
+		e, err := s.Current()
+		require.NoError(t, err)
+		events = append(events, e)
+	}
+	return events
+}
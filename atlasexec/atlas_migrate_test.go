@@ -10,15 +10,19 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqltool"
 	"github.com/stretchr/testify/require"
 )
 
@@ -809,7 +813,7 @@ func TestAtlasMigrate_LintWithLogin(t *testing.T) {
 			ConfigURL: atlasConfigURL,
 			Latest:    1,
 			Writer:    &buf,
-			Format:    "{{ .URL }}",
+			Format:    atlasexec.LintFormatURL,
 			Web:       true,
 		})
 		require.Equal(t, err, atlasexec.ErrLint)
@@ -927,3 +931,212 @@ func TestMigrate_Diff(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, output.Files, 0)
 }
+
+func TestMigrate_SetValidateLs(t *testing.T) {
+	ctx := context.Background()
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "set", "--format", "{{ json . }}", "--url", "sqlite://app.db", "1").
+		Stdout(`{"Added":[{"Version":"1","Description":"init"}],"Current":"1"}`)
+	s, err := c.MigrateSet(ctx, &atlasexec.MigrateSetParams{URL: "sqlite://app.db", Version: "1"})
+	require.NoError(t, err)
+	require.Equal(t, "1", s.Current)
+	require.Equal(t, []atlasexec.RevisionOp{{Version: "1", Description: "init"}}, s.Added)
+	_, err = c.MigrateSet(ctx, &atlasexec.MigrateSetParams{URL: "sqlite://app.db"})
+	require.EqualError(t, err, `atlasexec: command "migrate set" has invalid parameters: missing version to set`)
+
+	ex.On("migrate", "ls", "--short").Stdout("1\n2\n")
+	files, err := c.MigrateLs(ctx, &atlasexec.MigrateLsParams{Short: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, files)
+
+	ex.On("migrate", "validate", "--dev-url", "sqlite://dev?mode=memory").
+		Stderr(`Error: sql/migrate: executing statement "CREATE TABLE \"t1\" (c int);" from version "2": table "t1" already exists` + "\n").
+		ExitCode(1)
+	err = c.MigrateValidate(ctx, &atlasexec.MigrateValidateParams{DevURL: "sqlite://dev?mode=memory"})
+	var verr *atlasexec.MigrateValidateError
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, []*atlasexec.MigrateValidateFailure{{
+		Kind:    atlasexec.ValidateStatement,
+		Version: "2",
+		Stmt:    `CREATE TABLE "t1" (c int);`,
+		Text:    `table "t1" already exists`,
+	}}, verr.Failures)
+	require.EqualError(t, err, `migration directory is invalid: version "2": table "t1" already exists`)
+
+	ex.On("migrate", "validate", "--dir", "file://broken").
+		Stderr("Error: checksum mismatch\n").
+		ExitCode(1)
+	err = c.MigrateValidate(ctx, &atlasexec.MigrateValidateParams{DirURL: "file://broken"})
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, atlasexec.ValidateChecksum, verr.Failures[0].Kind)
+	require.ErrorIs(t, err, atlasexec.ErrChecksumMismatch)
+
+	// The created file can be located only in local directories.
+	_, err = c.MigrateNew(ctx, &atlasexec.MigrateNewParams{Env: "prod", Content: "SELECT 1;"})
+	require.EqualError(t, err, "atlasexec: migrate new: Content requires a local migration directory")
+}
+
+func TestMigrate_NewDirFormat(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// The executor creates the files of the golang-migrate format.
+	c, err := atlasexec.NewClient(dir, "atlas", atlasexec.WithExecutor(executorFunc(func(_ context.Context, inv *atlasexec.Invocation) error {
+		for _, name := range []string{"1_add_users.down.sql", "1_add_users.up.sql"} {
+			if err := os.WriteFile(filepath.Join(dir, "migrations", name), nil, 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	})))
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "migrations"), 0755))
+	_, err = c.MigrateNew(ctx, &atlasexec.MigrateNewParams{
+		DirFormat: atlasexec.DirFormatGolangMigrate,
+		Name:      "add_users",
+		Content:   "CREATE TABLE users (id int);",
+	})
+	require.EqualError(t, err, `atlasexec: command "migrate new" has invalid parameters: content is supported only for the atlas directory format`)
+	_, err = c.MigrateNew(ctx, &atlasexec.MigrateNewParams{
+		DirFormat: atlasexec.DirFormatGolangMigrate,
+		Name:      "add_users",
+	})
+	require.EqualError(t, err, fmt.Sprintf("atlasexec: migrate new: expected one created file in %q, got: 1_add_users.down.sql, 1_add_users.up.sql", filepath.Join(dir, "migrations")))
+}
+
+func TestMigrate_Import(t *testing.T) {
+	ctx := context.Background()
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithWorkingDir(t.TempDir()))
+	ex.OnMatch(atlasexectest.Prefix("migrate", "import"))
+	files, err := c.MigrateImport(ctx, &atlasexec.MigrateImportParams{
+		FromFS: fstest.MapFS{
+			"1_init.sql": {Data: []byte("-- +goose Up\nCREATE TABLE t(c int);\n-- +goose Down\nDROP TABLE t;\n")},
+		},
+		FromFormat: atlasexec.DirFormatGoose,
+		ToURL:      "file://migrations",
+	})
+	require.NoError(t, err)
+	require.Empty(t, files, "fake executor does not write files")
+	args := ex.Invocations()[0].Args
+	require.Equal(t, []string{"migrate", "import", "--from"}, args[:3])
+	require.Regexp(t, `^file:///.+\?format=goose$`, args[3])
+	require.Equal(t, []string{"--to", "file://migrations"}, args[4:])
+
+	_, err = c.MigrateImport(ctx, &atlasexec.MigrateImportParams{FromURL: "file://goose", FromFS: fstest.MapFS{}})
+	require.EqualError(t, err, `atlasexec: command "migrate import" has invalid parameters: FromURL and FromFS are mutually exclusive`)
+	_, err = c.Command(ctx, &atlasexec.MigrateImportParams{FromFS: fstest.MapFS{}})
+	require.EqualError(t, err, "atlasexec: migrate import: FromFS is supported only by MigrateImport, use FromURL")
+
+	// Directories are staged as-is, including the files
+	// that migrate.Dir implementations do not list.
+	local, err := sqltool.NewGolangMigrateDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, local.WriteFile("1_init.up.sql", []byte("CREATE TABLE t(c int);")))
+	require.NoError(t, local.WriteFile("1_init.down.sql", []byte("DROP TABLE t;")))
+	var staged []string
+	c, err = atlasexec.NewClient(t.TempDir(), "atlas", atlasexec.WithExecutor(executorFunc(func(_ context.Context, inv *atlasexec.Invocation) error {
+		u, err := url.Parse(inv.Args[3])
+		if err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(filepath.FromSlash(u.Path))
+		for _, e := range entries {
+			staged = append(staged, e.Name())
+		}
+		return err
+	})))
+	require.NoError(t, err)
+	_, err = c.MigrateImport(ctx, &atlasexec.MigrateImportParams{
+		FromFS:     local,
+		FromFormat: atlasexec.DirFormatGolangMigrate,
+		ToURL:      "file://migrations",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1_init.down.sql", "1_init.up.sql"}, staged)
+
+	// In-memory directories are staged using their files.
+	mem := &migrate.MemDir{}
+	require.NoError(t, mem.WriteFile("1_init.sql", []byte("CREATE TABLE t(c int);")))
+	staged = nil
+	_, err = c.MigrateImport(ctx, &atlasexec.MigrateImportParams{
+		FromFS:     mem,
+		FromFormat: atlasexec.DirFormatAtlas,
+		ToURL:      "file://migrations",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1_init.sql"}, staged)
+}
+
+func TestMigrate_StatusCheckpoint(t *testing.T) {
+	wd := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(wd, "migrations"), 0755))
+	for name, content := range map[string]string{
+		"1_init.sql":       "CREATE TABLE t1(c int);",
+		"2_t2.sql":         "CREATE TABLE t2(c int);",
+		"3_checkpoint.sql": "-- atlas:checkpoint v1\n\nCREATE TABLE t1(c int);\nCREATE TABLE t2(c int);",
+		"4_t3.sql":         "CREATE TABLE t3(c int);",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(wd, "migrations", name), []byte(content), 0644))
+	}
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithWorkingDir(wd))
+	// On a clean database, the CLI starts execution from the latest checkpoint.
+	ex.On("migrate", "status", "--format", "{{ json . }}", "--url", "sqlite://clean.db").Stdout(`{
+		"Available":[{"Name":"1_init.sql","Version":"1"},{"Name":"2_t2.sql","Version":"2"},{"Name":"3_checkpoint.sql","Version":"3"},{"Name":"4_t3.sql","Version":"4"}],
+		"Pending":[{"Name":"3_checkpoint.sql","Version":"3"},{"Name":"4_t3.sql","Version":"4"}],
+		"Current":"No migration applied yet","Next":"3","Status":"PENDING"
+	}`)
+	s, err := c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{URL: "sqlite://clean.db"})
+	require.NoError(t, err)
+	require.False(t, s.Available[0].Checkpoint)
+	require.True(t, s.Available[2].Checkpoint)
+	require.Equal(t, "v1", s.Available[2].Tag)
+	require.True(t, s.Pending[0].Checkpoint)
+	require.Equal(t, "v1", s.Pending[0].Tag)
+	require.False(t, s.Pending[1].Checkpoint)
+	require.Equal(t, "4", s.LatestVersion())
+	n, ok := s.Amount("")
+	require.Equal(t, uint64(2), n)
+	require.False(t, ok)
+
+	// Once revisions exist, the CLI skips the checkpoint files.
+	ex.On("migrate", "status", "--format", "{{ json . }}", "--url", "sqlite://app.db").Stdout(`{
+		"Available":[{"Name":"1_init.sql","Version":"1"},{"Name":"2_t2.sql","Version":"2"},{"Name":"3_checkpoint.sql","Version":"3"},{"Name":"4_t3.sql","Version":"4"}],
+		"Pending":[{"Name":"2_t2.sql","Version":"2"},{"Name":"4_t3.sql","Version":"4"}],
+		"Applied":[{"Version":"1","Description":"init","Applied":1,"Total":1}],
+		"Current":"1","Next":"2","Status":"PENDING"
+	}`)
+	s, err = c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{URL: "sqlite://app.db"})
+	require.NoError(t, err)
+	require.True(t, s.Available[2].Checkpoint)
+	require.False(t, s.Pending[0].Checkpoint)
+	require.False(t, s.Pending[1].Checkpoint)
+	n, _ = s.Amount("4")
+	require.Equal(t, uint64(2), n)
+
+	// With environments, the directory is taken from the CLI output.
+	ex.On("migrate", "status", "--format", "{{ json . }}", "--env", "local").Stdout(`{
+		"Env":{"Driver":"sqlite","Dir":"file://migrations"},
+		"Available":[{"Name":"1_init.sql","Version":"1"},{"Name":"3_checkpoint.sql","Version":"3"}],
+		"Pending":[{"Name":"3_checkpoint.sql","Version":"3"}],
+		"Current":"No migration applied yet","Next":"3","Status":"PENDING"
+	}`)
+	s, err = c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{Env: "local"})
+	require.NoError(t, err)
+	require.False(t, s.Available[0].Checkpoint)
+	require.True(t, s.Available[1].Checkpoint)
+	require.True(t, s.Pending[0].Checkpoint)
+
+	// Files of remote directories are not flagged.
+	ex.On("migrate", "status", "--format", "{{ json . }}", "--env", "cloud").Stdout(`{
+		"Env":{"Driver":"sqlite","Dir":"atlas://app"},
+		"Available":[{"Name":"1_init.sql","Version":"1"},{"Name":"3_checkpoint.sql","Version":"3"}],
+		"Pending":[{"Name":"3_checkpoint.sql","Version":"3"}],
+		"Current":"No migration applied yet","Next":"3","Status":"PENDING"
+	}`)
+	s, err = c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{Env: "cloud"})
+	require.NoError(t, err)
+	require.False(t, s.Available[1].Checkpoint)
+	require.False(t, s.Pending[0].Checkpoint)
+
+	_, err = c.MigrateCheckpoint(context.Background(), &atlasexec.MigrateCheckpointParams{Env: "prod", Tag: "v2"})
+	require.EqualError(t, err, "atlasexec: migrate checkpoint: Tag requires a local migration directory")
+}
@@ -16,6 +16,11 @@ type (
 		Version     string `json:"Version,omitempty"`
 		Description string `json:"Description,omitempty"`
 		Content     string `json:"Content,omitempty"`
+		// Checkpoint reports if the file is a checkpoint file, and
+		// Tag holds its tag, if defined. See MigrateCheckpoint. Both
+		// are set only for files of local directories.
+		Checkpoint bool   `json:"Checkpoint,omitempty"`
+		Tag        string `json:"Tag,omitempty"`
 	}
 	// AppliedFile is part of a MigrateApply containing information about an applied file in a migration attempt.
 	AppliedFile struct {
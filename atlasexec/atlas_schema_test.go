@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -903,3 +904,31 @@ func TestSchema_Lint(t *testing.T) {
 		})
 	}
 }
+
+func TestSchema_Diff(t *testing.T) {
+	ctx := context.Background()
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("schema", "diff", "--from", "sqlite://a.db", "--to", "file://schema.sql,file://views.sql", "--dev-url", "sqlite://dev?mode=memory", "--format", "{{ sql . }}").
+		Stdout("-- Create \"t2\" table\nCREATE TABLE `t2` (`c` int NULL);\n-- Drop \"t1\" table\nDROP TABLE `t1`;\n")
+	d, err := c.SchemaDiff(ctx, &atlasexec.SchemaDiffParams{
+		From:   []string{"sqlite://a.db"},
+		To:     []string{"file://schema.sql", "file://views.sql"},
+		DevURL: "sqlite://dev?mode=memory",
+	})
+	require.NoError(t, err)
+	require.False(t, d.Empty())
+	require.Len(t, d.Stmts, 2)
+	require.Equal(t, "CREATE TABLE `t2` (`c` int NULL);", d.Stmts[0].Text)
+	require.Equal(t, []string{"-- Create \"t2\" table\n"}, d.Stmts[0].Comments)
+	require.Equal(t, "DROP TABLE `t1`;", d.Stmts[1].Text)
+
+	// In-sync schemas.
+	ex.On("schema", "diff", "--env", "prod", "--schema", "app", "--format", "{{ sql . }}").
+		Stdout("Schemas are synced, no changes to be made.\n")
+	d, err = c.SchemaDiff(ctx, &atlasexec.SchemaDiffParams{Env: "prod", Schema: []string{"app"}})
+	require.NoError(t, err)
+	require.True(t, d.Empty())
+
+	_, err = c.SchemaDiff(ctx, &atlasexec.SchemaDiffParams{From: []string{"sqlite://a.db"}})
+	require.EqualError(t, err, `atlasexec: command "schema diff" has invalid parameters: missing required flag --to`)
+}
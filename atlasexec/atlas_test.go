@@ -1,16 +1,24 @@
 package atlasexec_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
@@ -40,6 +48,253 @@ func TestNewClient(t *testing.T) {
 	require.ErrorContains(t, err, `no such file or directory`)
 }
 
+func TestExecutor(t *testing.T) {
+	var rec recordExecutor
+	// The exec path is not looked up when a custom executor is used.
+	c, err := atlasexec.NewClient(t.TempDir(), "/foo/atlas", atlasexec.WithExecutor(&rec))
+	require.NoError(t, err)
+	rec.stdout = `{"Org":"boring"}`
+	v, err := c.WhoAmI(context.Background(), &atlasexec.WhoAmIParams{})
+	require.NoError(t, err)
+	require.Equal(t, "boring", v.Org)
+	require.Len(t, rec.calls, 1)
+	require.Equal(t, "/foo/atlas", rec.calls[0].Path)
+	require.Equal(t, []string{"whoami", "--format", "{{ json . }}"}, rec.calls[0].Args)
+	require.Contains(t, rec.calls[0].Env, "ATLAS_NO_UPDATE_NOTIFIER=1")
+
+	// Exit codes are reported by the executor errors.
+	rec.stdout, rec.stderr, rec.err = "", "Error: boom", exitError(2)
+	_, err = c.WhoAmI(context.Background(), &atlasexec.WhoAmIParams{})
+	require.EqualError(t, err, "Error: boom")
+	var cliErr *atlasexec.Error
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, 2, cliErr.ExitCode())
+
+	// Streams are also executed by the executor.
+	rec.stdout, rec.stderr, rec.err = `{"type":"message","content":"hello"}`, "", nil
+	s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+	require.NoError(t, err)
+	require.True(t, s.Next())
+	m, err := s.Current()
+	require.NoError(t, err)
+	require.Equal(t, "hello", m.Content)
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+	require.Equal(t, []string{"copilot", "-q", "hi"}, rec.calls[2].Args)
+}
+
+func TestClient_With(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("logout")
+	var stdout bytes.Buffer
+	c2 := c.With(
+		atlasexec.WithWorkingDir("dir"),
+		atlasexec.WithEnv(atlasexec.Environ{"FOO": "bar"}),
+		atlasexec.WithStdout(&stdout),
+	)
+	require.NoError(t, c2.Logout(context.Background()))
+	require.NoError(t, c.Logout(context.Background()))
+	// Per-call options are applied on top of the client configuration.
+	require.NoError(t, c2.Logout(context.Background(), atlasexec.WithEnv(atlasexec.Environ{"BAZ": "qux"})))
+	invs := ex.Invocations()
+	require.Len(t, invs, 3)
+	require.Equal(t, "dir", invs[0].Dir)
+	require.Contains(t, invs[0].Env, "FOO=bar")
+	require.Empty(t, invs[1].Dir, "parent client should not be modified")
+	require.NotContains(t, invs[1].Env, "FOO=bar")
+	require.Equal(t, "dir", invs[2].Dir)
+	require.Subset(t, invs[2].Env, []string{"FOO=bar", "BAZ=qux"})
+
+	// WithWorkDir does not modify the client.
+	require.NoError(t, c.WithWorkDir("other", func(c *atlasexec.Client) error {
+		return c.Logout(context.Background())
+	}))
+	require.NoError(t, c.Logout(context.Background()))
+	invs = ex.Invocations()
+	require.Equal(t, "other", invs[3].Dir)
+	require.Empty(t, invs[4].Dir)
+}
+
+func TestClient_Timeout(t *testing.T) {
+	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithExecutor(executorFunc(func(ctx context.Context, _ *atlasexec.Invocation) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})))
+	require.NoError(t, err)
+	err = c.Logout(context.Background(), atlasexec.WithTimeout(10*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_GracefulCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupts are not supported on Windows")
+	}
+	// The script reports the applied files once interrupted, like the atlas-cli.
+	path := filepath.Join(t.TempDir(), "atlas")
+	require.NoError(t, os.WriteFile(path, []byte(`#!/bin/sh
+trap 'kill $! 2>/dev/null; echo "{\"Target\":\"2\",\"Applied\":[{\"Name\":\"1.sql\",\"Version\":\"1\"}],\"Error\":\"context canceled\"}"; exit 1' INT
+echo started >&2
+sleep 10 >/dev/null 2>&1 &
+wait
+`), 0755))
+	run := func(t *testing.T, c *atlasexec.Client) ([]*atlasexec.MigrateApply, error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r, w := io.Pipe()
+		defer r.Close()
+		go func() {
+			// Cancel once the script is running.
+			io.ReadFull(r, make([]byte, 1))
+			cancel()
+			io.Copy(io.Discard, r)
+		}()
+		return c.MigrateApplySlice(ctx, &atlasexec.MigrateApplyParams{}, atlasexec.WithStderr(w))
+	}
+
+	t.Run("Graceful", func(t *testing.T) {
+		c, err := atlasexec.NewClient("", path, atlasexec.WithGracefulCancel(5*time.Second))
+		require.NoError(t, err)
+		_, err = run(t, c)
+		require.ErrorIs(t, err, atlasexec.ErrCanceled)
+		require.ErrorIs(t, err, context.Canceled)
+		var merr *atlasexec.MigrateApplyError
+		require.ErrorAs(t, err, &merr)
+		require.Len(t, merr.Result, 1)
+		require.Equal(t, "2", merr.Result[0].Target)
+		require.Len(t, merr.Result[0].Applied, 1)
+		require.Equal(t, "1.sql", merr.Result[0].Applied[0].Name)
+	})
+
+	t.Run("Kill", func(t *testing.T) {
+		c, err := atlasexec.NewClient("", path)
+		require.NoError(t, err)
+		_, err = run(t, c)
+		require.ErrorIs(t, err, atlasexec.ErrCanceled)
+		var merr *atlasexec.MigrateApplyError
+		require.False(t, errors.As(err, &merr), "no results are reported when the process is killed")
+	})
+}
+
+func TestStream_Close(t *testing.T) {
+	// waitGoroutines waits for the goroutines started by the test to exit.
+	waitGoroutines := func(t *testing.T, n int) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > n; time.Sleep(10 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("stream goroutines were leaked: %d > %d", runtime.NumGoroutine(), n)
+			}
+		}
+	}
+	msg := `{"type":"message","content":"hello"}` + "\n"
+
+	t.Run("Partial", func(t *testing.T) {
+		n := runtime.NumGoroutine()
+		c, ex := atlasexectest.NewClient(t)
+		ex.On("copilot", "-q", "hi").Stdout(strings.Repeat(msg, 1000))
+		s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+		require.NoError(t, err)
+		require.True(t, s.Next())
+		err = s.Close()
+		require.ErrorIs(t, err, atlasexec.ErrCanceled)
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, s.Next())
+		require.Equal(t, err, s.Close(), "Close is idempotent")
+		waitGoroutines(t, n)
+	})
+
+	t.Run("Consumed", func(t *testing.T) {
+		n := runtime.NumGoroutine()
+		c, ex := atlasexectest.NewClient(t)
+		ex.On("copilot", "-q", "hi").Stdout(strings.Repeat(msg, 3))
+		s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+		require.NoError(t, err)
+		for s.Next() {
+		}
+		require.NoError(t, s.Close())
+		require.NoError(t, s.Err())
+		waitGoroutines(t, n)
+	})
+
+	t.Run("Process", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("shell scripts are not supported on Windows")
+		}
+		path := filepath.Join(t.TempDir(), "atlas")
+		require.NoError(t, os.WriteFile(path, []byte(`#!/bin/sh
+while true; do echo '{"type":"message","content":"hello"}'; done
+`), 0755))
+		n := runtime.NumGoroutine()
+		c, err := atlasexec.NewClient("", path)
+		require.NoError(t, err)
+		s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			require.True(t, s.Next())
+		}
+		require.ErrorIs(t, s.Close(), atlasexec.ErrCanceled)
+		waitGoroutines(t, n)
+	})
+}
+
+func TestClient_Concurrent(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.OnMatch(atlasexectest.Prefix("migrate", "apply")).Stdout(`{"Target":"1"}`)
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 20)
+	)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dir := fmt.Sprintf("dir%d", i)
+			_, err := c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{Env: dir},
+				atlasexec.WithWorkingDir(dir),
+				atlasexec.WithEnv(atlasexec.Environ{"DIR": dir}),
+			)
+			errs <- err
+			c.SetStderr(io.Discard)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	invs := ex.Invocations()
+	require.Len(t, invs, 20)
+	for _, inv := range invs {
+		require.Contains(t, inv.Env, "DIR="+inv.Dir)
+		require.Equal(t, inv.Dir, inv.Args[len(inv.Args)-1])
+	}
+}
+
+type (
+	executorFunc   func(context.Context, *atlasexec.Invocation) error
+	recordExecutor struct {
+		stdout, stderr string
+		err            error
+		calls          []*atlasexec.Invocation
+	}
+	exitError int
+)
+
+func (r *recordExecutor) Exec(_ context.Context, inv *atlasexec.Invocation) error {
+	r.calls = append(r.calls, inv)
+	if _, err := io.WriteString(inv.Stdout, r.stdout); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(inv.Stderr, r.stderr); err != nil {
+		return err
+	}
+	return r.err
+}
+
+func (f executorFunc) Exec(ctx context.Context, inv *atlasexec.Invocation) error { return f(ctx, inv) }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e exitError) ExitCode() int { return int(e) }
+
 func TestVersion(t *testing.T) {
 	wd, err := os.Getwd()
 	require.NoError(t, err)
@@ -0,0 +1,260 @@
+// Package atlasexectest provides a scriptable fake of the atlas-cli for testing
+// code that depends on the atlasexec package, without requiring the real binary
+// or a database.
+//
+//	c, ex := atlasexectest.NewClient(t)
+//	ex.On("migrate", "apply", "--format", "{{ json . }}", "--env", "prod").
+//		Stdout(`{"Target":"20240101000000"}`)
+//	res, err := c.MigrateApply(ctx, &atlasexec.MigrateApplyParams{Env: "prod"})
+package atlasexectest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+)
+
+type (
+	// Executor is a fake atlasexec.Executor. Tests register the expected argument
+	// patterns using On or OnMatch, and the canned output that is returned to the
+	// Client when an invocation matches them.
+	Executor struct {
+		mu         sync.Mutex
+		calls      []*Call
+		recorded   []*atlasexec.Invocation
+		unexpected [][]string
+	}
+	// Call is a registered expectation and its canned response.
+	Call struct {
+		e              *Executor
+		match          Matcher
+		stdout, stderr string
+		code           int
+		codeSet        bool
+		times, count   int
+		err            error
+	}
+	// Matcher reports whether the given arguments match an expectation.
+	Matcher func(args []string) bool
+	// ExitError is returned by the Executor to report a non-zero exit status.
+	ExitError struct {
+		Code int
+	}
+)
+
+// New returns a new fake Executor. If t is not nil, the test is failed on cleanup
+// in case the Executor received invocations that did not match any expectation.
+func New(t testing.TB) *Executor {
+	e := &Executor{}
+	if t != nil {
+		t.Cleanup(func() {
+			for _, args := range e.Unexpected() {
+				t.Errorf("atlasexectest: unexpected invocation: %q", args)
+			}
+		})
+	}
+	return e
+}
+
+// NewClient returns a new atlasexec.Client that runs all commands using a fake Executor.
+func NewClient(t testing.TB, opts ...atlasexec.ClientOption) (*atlasexec.Client, *Executor) {
+	e := New(t)
+	c, err := atlasexec.NewClient("", "atlas", append(opts, atlasexec.WithExecutor(e))...)
+	if err != nil {
+		t.Fatalf("atlasexectest: creating client: %v", err)
+	}
+	return c, e
+}
+
+// On registers an expectation for an invocation with exactly the given arguments.
+func (e *Executor) On(args ...string) *Call {
+	return e.OnMatch(Args(args...))
+}
+
+// OnMatch registers an expectation for invocations matched by m.
+func (e *Executor) OnMatch(m Matcher) *Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c := &Call{e: e, match: m}
+	e.calls = append(e.calls, c)
+	return c
+}
+
+// Invocations returns all invocations received by the Executor.
+func (e *Executor) Invocations() []*atlasexec.Invocation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.recorded)
+}
+
+// Unexpected returns the arguments of the invocations that did not match any expectation.
+func (e *Executor) Unexpected() [][]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.unexpected)
+}
+
+// Exec implements the atlasexec.Executor interface.
+func (e *Executor) Exec(ctx context.Context, inv *atlasexec.Invocation) error {
+	c := e.lookup(inv)
+	if c == nil {
+		fmt.Fprintf(inv.Stderr, "atlasexectest: unexpected invocation: %s", strings.Join(inv.Args, " "))
+		return &ExitError{Code: 1}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.err != nil {
+		return c.err
+	}
+	if _, err := io.WriteString(inv.Stdout, c.stdout); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(inv.Stderr, c.stderr); err != nil {
+		return err
+	}
+	if code := c.exitCode(); code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
+
+// lookup records the invocation and returns a snapshot of the first call
+// matching it, as the response of the call may be changed concurrently.
+func (e *Executor) lookup(inv *atlasexec.Invocation) *Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.recorded = append(e.recorded, inv)
+	for _, c := range e.calls {
+		if (c.times == 0 || c.count < c.times) && c.match(inv.Args) {
+			c.count++
+			snap := *c
+			return &snap
+		}
+	}
+	e.unexpected = append(e.unexpected, inv.Args)
+	return nil
+}
+
+// Stdout sets the output written to stdout.
+func (c *Call) Stdout(s string) *Call {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.stdout = s
+	return c
+}
+
+// Stderr sets the output written to stderr. Unless ExitCode
+// is called, a non-empty stderr causes the exit code to be 1.
+func (c *Call) Stderr(s string) *Call {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.stderr = s
+	return c
+}
+
+// ExitCode sets the exit code of the command.
+func (c *Call) ExitCode(code int) *Call {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.code, c.codeSet = code, true
+	return c
+}
+
+// Error sets the error returned by the Executor, for example,
+// to simulate a failure in starting the process.
+func (c *Call) Error(err error) *Call {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.err = err
+	return c
+}
+
+// Times limits the number of invocations the call can match.
+// By default, a call matches any number of invocations.
+func (c *Call) Times(n int) *Call {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.times = n
+	return c
+}
+
+// Once is a shorthand for Times(1).
+func (c *Call) Once() *Call {
+	return c.Times(1)
+}
+
+// Count returns the number of invocations matched by the call.
+func (c *Call) Count() int {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	return c.count
+}
+
+func (c *Call) exitCode() int {
+	switch {
+	case c.codeSet:
+		return c.code
+	case c.stderr != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Args returns a Matcher that matches the exact given arguments.
+func Args(args ...string) Matcher {
+	return func(a []string) bool {
+		return slices.Equal(a, args)
+	}
+}
+
+// Prefix returns a Matcher that matches arguments starting with the given ones,
+// for example, Prefix("migrate", "apply") matches all 'migrate apply' invocations.
+func Prefix(args ...string) Matcher {
+	return func(a []string) bool {
+		return len(a) >= len(args) && slices.Equal(a[:len(args)], args)
+	}
+}
+
+// Flag returns a Matcher that matches arguments containing the given flag and value.
+func Flag(name, value string) Matcher {
+	return func(a []string) bool {
+		for i := 0; i < len(a)-1; i++ {
+			if a[i] == name && a[i+1] == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All returns a Matcher that matches if all the given matchers match.
+func All(ms ...Matcher) Matcher {
+	return func(a []string) bool {
+		for _, m := range ms {
+			if !m(a) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the exit code of the command.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+var _ atlasexec.Executor = (*Executor)(nil)
@@ -0,0 +1,169 @@
+package atlasexectest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_MigrateApply(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--format", "{{ json . }}", "--env", "prod", "--url", "sqlite://app.db", "--tx-mode", "all", "2").
+		Stdout(`{"Driver":"sqlite3","Target":"20240101000000"}`)
+	res, err := c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{
+		Env:    "prod",
+		URL:    "sqlite://app.db",
+		TxMode: atlasexec.TxModeAll,
+		Amount: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "sqlite3", res.Driver)
+	require.Equal(t, "20240101000000", res.Target)
+	require.Len(t, ex.Invocations(), 1)
+
+	// Partial results are decoded into MigrateApplyError.
+	ex.OnMatch(atlasexectest.All(
+		atlasexectest.Prefix("migrate", "apply"),
+		atlasexectest.Flag("--env", "broken"),
+	)).
+		Stdout(`{"Target":"20240101000000","Error":"near \"broken\": syntax error"}`).
+		ExitCode(1)
+	_, err = c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{Env: "broken"})
+	var applyErr *atlasexec.MigrateApplyError
+	require.ErrorAs(t, err, &applyErr)
+	require.EqualError(t, err, `near "broken": syntax error`)
+	require.Equal(t, "20240101000000", applyErr.Result[0].Target)
+}
+
+func TestExecutor_SchemaApply(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("schema", "apply", "--format", "{{ json . }}", "--url", "sqlite://app.db", "--to", "file://schema.hcl", "--auto-approve").
+		Stdout(`{"Error":"create table: table exists"}`).
+		Stderr("Error: create table: table exists")
+	_, err := c.SchemaApply(context.Background(), &atlasexec.SchemaApplyParams{
+		URL:         "sqlite://app.db",
+		To:          "file://schema.hcl",
+		AutoApprove: true,
+	})
+	var applyErr *atlasexec.SchemaApplyError
+	require.ErrorAs(t, err, &applyErr)
+	require.Equal(t, "Error: create table: table exists", applyErr.Stderr)
+	require.Equal(t, "create table: table exists", applyErr.Result[0].Error)
+}
+
+func TestExecutor_SchemaPlan(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("schema", "plan", "--format", "{{ json . }}", "--dev-url", "sqlite://dev?mode=memory", "--from", "file://1.hcl", "--to", "file://2.hcl", "--repo", "app", "--dry-run").
+		Stdout(`{"Repo":"app","File":{"Name":"add_users"}}`)
+	plan, err := c.SchemaPlan(context.Background(), &atlasexec.SchemaPlanParams{
+		DevURL: "sqlite://dev?mode=memory",
+		From:   []string{"file://1.hcl"},
+		To:     []string{"file://2.hcl"},
+		Repo:   "app",
+		DryRun: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "app", plan.Repo)
+	require.Equal(t, "add_users", plan.File.Name)
+}
+
+func TestExecutor_Errors(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("whoami", "--format", "{{ json . }}").
+		Stderr("Error: command requires 'atlas login'").
+		Once()
+	_, err := c.WhoAmI(context.Background(), &atlasexec.WhoAmIParams{})
+	require.ErrorIs(t, err, atlasexec.ErrRequireLogin)
+
+	ex.On("logout").ExitCode(3)
+	err = c.Logout(context.Background())
+	var cliErr *atlasexec.Error
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, 3, cliErr.ExitCode())
+
+	startErr := errors.New("exec: permission denied")
+	ex.On("version").Error(startErr)
+	_, err = c.Version(context.Background())
+	require.ErrorIs(t, err, startErr)
+}
+
+func TestExecutor_Unexpected(t *testing.T) {
+	ex := atlasexectest.New(nil)
+	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithExecutor(ex))
+	require.NoError(t, err)
+	call := ex.On("logout").Once()
+	require.NoError(t, c.Logout(context.Background()))
+	require.Equal(t, 1, call.Count())
+	// The call was consumed.
+	err = c.Logout(context.Background())
+	require.EqualError(t, err, "atlasexectest: unexpected invocation: logout")
+	require.Equal(t, [][]string{{"logout"}}, ex.Unexpected())
+}
+
+func TestExecutor_Concurrent(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	call := ex.On("logout")
+	done := make(chan error)
+	go func() {
+		defer close(done)
+		for range 100 {
+			if err := c.Logout(context.Background()); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+	// Responses can be changed while the client runs.
+	for range 100 {
+		call.Stdout("bye").Stderr("").ExitCode(0)
+	}
+	require.NoError(t, <-done)
+	require.Equal(t, 100, call.Count())
+}
+
+func TestFakeClient(t *testing.T) {
+	// deploy depends on a subset of the client commands.
+	deploy := func(ctx context.Context, m atlasexec.Migrator) (string, error) {
+		s, err := m.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{Env: "prod"})
+		if err != nil {
+			return "", err
+		}
+		if len(s.Pending) == 0 {
+			return s.Current, nil
+		}
+		r, err := m.MigrateApply(ctx, &atlasexec.MigrateApplyParams{Env: "prod"})
+		if err != nil {
+			return "", err
+		}
+		return r.Target, nil
+	}
+	f := &atlasexectest.FakeClient{
+		MigrateStatusFunc: func(context.Context, *atlasexec.MigrateStatusParams, ...atlasexec.ClientOption) (*atlasexec.MigrateStatus, error) {
+			return &atlasexec.MigrateStatus{Current: "1", Pending: []atlasexec.File{{Version: "2"}}}, nil
+		},
+	}
+	_, err := deploy(context.Background(), f)
+	require.EqualError(t, err, "atlasexectest: FakeClient.MigrateApply is not implemented")
+
+	f.MigrateApplyFunc = func(_ context.Context, p *atlasexec.MigrateApplyParams, _ ...atlasexec.ClientOption) (*atlasexec.MigrateApply, error) {
+		return &atlasexec.MigrateApply{Env: atlasexec.Env{Driver: "sqlite3"}, Target: "2"}, nil
+	}
+	v, err := deploy(context.Background(), f)
+	require.NoError(t, err)
+	require.Equal(t, "2", v)
+
+	calls := f.Calls()
+	require.Len(t, calls, 4)
+	require.Equal(t, []string{"MigrateStatus", "MigrateApply", "MigrateStatus", "MigrateApply"}, []string{calls[0].Method, calls[1].Method, calls[2].Method, calls[3].Method})
+	applies := f.CallsOf("MigrateApply")
+	require.Len(t, applies, 2)
+	require.Equal(t, "prod", applies[1].Args[1].(*atlasexec.MigrateApplyParams).Env)
+
+	// The client and the fake are interchangeable.
+	var _ atlasexec.Interface = f
+	var _ atlasexec.Interface = (*atlasexec.Client)(nil)
+}
@@ -0,0 +1,470 @@
+// Code generated by internal/genfake. DO NOT EDIT.
+
+package atlasexectest
+
+import (
+	"context"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+)
+
+// FakeClient is a fake implementation of atlasexec.Interface. Every method records
+// its call and invokes the function set in the field with the same name and the
+// "Func" suffix, e.g. MigrateApplyFunc. If the function is not set, the method
+// returns an error.
+type FakeClient struct {
+	fakeCalls
+	MigrateApplyFunc       func(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) (*atlasexec.MigrateApply, error)
+	MigrateApplySliceFunc  func(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) ([]*atlasexec.MigrateApply, error)
+	MigrateApplyStreamFunc func(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) (atlasexec.Stream[*atlasexec.MigrateEvent], error)
+	MigrateCheckpointFunc  func(ctx context.Context, params *atlasexec.MigrateCheckpointParams, opts ...atlasexec.ClientOption) (*atlasexec.File, error)
+	MigrateDownFunc        func(ctx context.Context, params *atlasexec.MigrateDownParams, opts ...atlasexec.ClientOption) (*atlasexec.MigrateDown, error)
+	MigrateDownStreamFunc  func(ctx context.Context, params *atlasexec.MigrateDownParams, opts ...atlasexec.ClientOption) (atlasexec.Stream[*atlasexec.MigrateEvent], error)
+	MigrateDiffFunc        func(ctx context.Context, params *atlasexec.MigrateDiffParams, opts ...atlasexec.ClientOption) (*atlasexec.MigrateDiff, error)
+	MigrateHashFunc        func(ctx context.Context, params *atlasexec.MigrateHashParams, opts ...atlasexec.ClientOption) error
+	MigrateImportFunc      func(ctx context.Context, params *atlasexec.MigrateImportParams, opts ...atlasexec.ClientOption) ([]*atlasexec.File, error)
+	MigrateLintFunc        func(ctx context.Context, params *atlasexec.MigrateLintParams, opts ...atlasexec.ClientOption) (*atlasexec.SummaryReport, error)
+	MigrateLintErrorFunc   func(ctx context.Context, params *atlasexec.MigrateLintParams, opts ...atlasexec.ClientOption) error
+	MigrateLsFunc          func(ctx context.Context, params *atlasexec.MigrateLsParams, opts ...atlasexec.ClientOption) ([]string, error)
+	MigrateNewFunc         func(ctx context.Context, params *atlasexec.MigrateNewParams, opts ...atlasexec.ClientOption) (*atlasexec.File, error)
+	MigratePushFunc        func(ctx context.Context, params *atlasexec.MigratePushParams, opts ...atlasexec.ClientOption) (string, error)
+	MigrateRebaseFunc      func(ctx context.Context, params *atlasexec.MigrateRebaseParams, opts ...atlasexec.ClientOption) error
+	MigrateSetFunc         func(ctx context.Context, params *atlasexec.MigrateSetParams, opts ...atlasexec.ClientOption) (*atlasexec.MigrateSet, error)
+	MigrateStatusFunc      func(ctx context.Context, params *atlasexec.MigrateStatusParams, opts ...atlasexec.ClientOption) (*atlasexec.MigrateStatus, error)
+	MigrateTestFunc        func(ctx context.Context, params *atlasexec.MigrateTestParams, opts ...atlasexec.ClientOption) (string, error)
+	MigrateValidateFunc    func(ctx context.Context, params *atlasexec.MigrateValidateParams, opts ...atlasexec.ClientOption) error
+	SchemaApplyFunc        func(ctx context.Context, params *atlasexec.SchemaApplyParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaApply, error)
+	SchemaApplySliceFunc   func(ctx context.Context, params *atlasexec.SchemaApplyParams, opts ...atlasexec.ClientOption) ([]*atlasexec.SchemaApply, error)
+	SchemaCleanFunc        func(ctx context.Context, params *atlasexec.SchemaCleanParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaClean, error)
+	SchemaDiffFunc         func(ctx context.Context, params *atlasexec.SchemaDiffParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaDiff, error)
+	SchemaInspectFunc      func(ctx context.Context, params *atlasexec.SchemaInspectParams, opts ...atlasexec.ClientOption) (string, error)
+	SchemaLintFunc         func(ctx context.Context, params *atlasexec.SchemaLintParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaLintReport, error)
+	SchemaPushFunc         func(ctx context.Context, params *atlasexec.SchemaPushParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaPush, error)
+	SchemaTestFunc         func(ctx context.Context, params *atlasexec.SchemaTestParams, opts ...atlasexec.ClientOption) (string, error)
+	SchemaPlanFunc         func(ctx context.Context, params *atlasexec.SchemaPlanParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaPlan, error)
+	SchemaPlanApproveFunc  func(ctx context.Context, params *atlasexec.SchemaPlanApproveParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaPlanApprove, error)
+	SchemaPlanLintFunc     func(ctx context.Context, params *atlasexec.SchemaPlanLintParams, opts ...atlasexec.ClientOption) (*atlasexec.SchemaPlan, error)
+	SchemaPlanListFunc     func(ctx context.Context, params *atlasexec.SchemaPlanListParams, opts ...atlasexec.ClientOption) ([]atlasexec.SchemaPlanFile, error)
+	SchemaPlanPullFunc     func(ctx context.Context, params *atlasexec.SchemaPlanPullParams, opts ...atlasexec.ClientOption) (string, error)
+	SchemaPlanPushFunc     func(ctx context.Context, params *atlasexec.SchemaPlanPushParams, opts ...atlasexec.ClientOption) (string, error)
+	SchemaPlanValidateFunc func(ctx context.Context, params *atlasexec.SchemaPlanValidateParams, opts ...atlasexec.ClientOption) error
+	LoginFunc              func(ctx context.Context, params *atlasexec.LoginParams, opts ...atlasexec.ClientOption) error
+	LogoutFunc             func(ctx context.Context, opts ...atlasexec.ClientOption) error
+	WhoAmIFunc             func(ctx context.Context, params *atlasexec.WhoAmIParams, opts ...atlasexec.ClientOption) (*atlasexec.WhoAmI, error)
+	CopilotFunc            func(ctx context.Context, params *atlasexec.CopilotParams, opts ...atlasexec.ClientOption) (atlasexec.Copilot, error)
+	CopilotStreamFunc      func(ctx context.Context, params *atlasexec.CopilotParams, opts ...atlasexec.ClientOption) (atlasexec.Stream[*atlasexec.CopilotMessage], error)
+	VersionFunc            func(ctx context.Context, opts ...atlasexec.ClientOption) (*atlasexec.Version, error)
+	RunFunc                func(ctx context.Context, args ...string) (string, error)
+}
+
+var _ atlasexec.Interface = (*FakeClient)(nil)
+
+// MigrateApply implements atlasexec.Interface.
+func (f *FakeClient) MigrateApply(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.MigrateApply, r1 error) {
+	f.record("MigrateApply", ctx, params, opts)
+	if f.MigrateApplyFunc == nil {
+		r1 = notImplemented("MigrateApply")
+		return
+	}
+	return f.MigrateApplyFunc(ctx, params, opts...)
+}
+
+// MigrateApplySlice implements atlasexec.Interface.
+func (f *FakeClient) MigrateApplySlice(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) (r0 []*atlasexec.MigrateApply, r1 error) {
+	f.record("MigrateApplySlice", ctx, params, opts)
+	if f.MigrateApplySliceFunc == nil {
+		r1 = notImplemented("MigrateApplySlice")
+		return
+	}
+	return f.MigrateApplySliceFunc(ctx, params, opts...)
+}
+
+// MigrateApplyStream implements atlasexec.Interface.
+func (f *FakeClient) MigrateApplyStream(ctx context.Context, params *atlasexec.MigrateApplyParams, opts ...atlasexec.ClientOption) (r0 atlasexec.Stream[*atlasexec.MigrateEvent], r1 error) {
+	f.record("MigrateApplyStream", ctx, params, opts)
+	if f.MigrateApplyStreamFunc == nil {
+		r1 = notImplemented("MigrateApplyStream")
+		return
+	}
+	return f.MigrateApplyStreamFunc(ctx, params, opts...)
+}
+
+// MigrateCheckpoint implements atlasexec.Interface.
+func (f *FakeClient) MigrateCheckpoint(ctx context.Context, params *atlasexec.MigrateCheckpointParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.File, r1 error) {
+	f.record("MigrateCheckpoint", ctx, params, opts)
+	if f.MigrateCheckpointFunc == nil {
+		r1 = notImplemented("MigrateCheckpoint")
+		return
+	}
+	return f.MigrateCheckpointFunc(ctx, params, opts...)
+}
+
+// MigrateDown implements atlasexec.Interface.
+func (f *FakeClient) MigrateDown(ctx context.Context, params *atlasexec.MigrateDownParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.MigrateDown, r1 error) {
+	f.record("MigrateDown", ctx, params, opts)
+	if f.MigrateDownFunc == nil {
+		r1 = notImplemented("MigrateDown")
+		return
+	}
+	return f.MigrateDownFunc(ctx, params, opts...)
+}
+
+// MigrateDownStream implements atlasexec.Interface.
+func (f *FakeClient) MigrateDownStream(ctx context.Context, params *atlasexec.MigrateDownParams, opts ...atlasexec.ClientOption) (r0 atlasexec.Stream[*atlasexec.MigrateEvent], r1 error) {
+	f.record("MigrateDownStream", ctx, params, opts)
+	if f.MigrateDownStreamFunc == nil {
+		r1 = notImplemented("MigrateDownStream")
+		return
+	}
+	return f.MigrateDownStreamFunc(ctx, params, opts...)
+}
+
+// MigrateDiff implements atlasexec.Interface.
+func (f *FakeClient) MigrateDiff(ctx context.Context, params *atlasexec.MigrateDiffParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.MigrateDiff, r1 error) {
+	f.record("MigrateDiff", ctx, params, opts)
+	if f.MigrateDiffFunc == nil {
+		r1 = notImplemented("MigrateDiff")
+		return
+	}
+	return f.MigrateDiffFunc(ctx, params, opts...)
+}
+
+// MigrateHash implements atlasexec.Interface.
+func (f *FakeClient) MigrateHash(ctx context.Context, params *atlasexec.MigrateHashParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("MigrateHash", ctx, params, opts)
+	if f.MigrateHashFunc == nil {
+		r0 = notImplemented("MigrateHash")
+		return
+	}
+	return f.MigrateHashFunc(ctx, params, opts...)
+}
+
+// MigrateImport implements atlasexec.Interface.
+func (f *FakeClient) MigrateImport(ctx context.Context, params *atlasexec.MigrateImportParams, opts ...atlasexec.ClientOption) (r0 []*atlasexec.File, r1 error) {
+	f.record("MigrateImport", ctx, params, opts)
+	if f.MigrateImportFunc == nil {
+		r1 = notImplemented("MigrateImport")
+		return
+	}
+	return f.MigrateImportFunc(ctx, params, opts...)
+}
+
+// MigrateLint implements atlasexec.Interface.
+func (f *FakeClient) MigrateLint(ctx context.Context, params *atlasexec.MigrateLintParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SummaryReport, r1 error) {
+	f.record("MigrateLint", ctx, params, opts)
+	if f.MigrateLintFunc == nil {
+		r1 = notImplemented("MigrateLint")
+		return
+	}
+	return f.MigrateLintFunc(ctx, params, opts...)
+}
+
+// MigrateLintError implements atlasexec.Interface.
+func (f *FakeClient) MigrateLintError(ctx context.Context, params *atlasexec.MigrateLintParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("MigrateLintError", ctx, params, opts)
+	if f.MigrateLintErrorFunc == nil {
+		r0 = notImplemented("MigrateLintError")
+		return
+	}
+	return f.MigrateLintErrorFunc(ctx, params, opts...)
+}
+
+// MigrateLs implements atlasexec.Interface.
+func (f *FakeClient) MigrateLs(ctx context.Context, params *atlasexec.MigrateLsParams, opts ...atlasexec.ClientOption) (r0 []string, r1 error) {
+	f.record("MigrateLs", ctx, params, opts)
+	if f.MigrateLsFunc == nil {
+		r1 = notImplemented("MigrateLs")
+		return
+	}
+	return f.MigrateLsFunc(ctx, params, opts...)
+}
+
+// MigrateNew implements atlasexec.Interface.
+func (f *FakeClient) MigrateNew(ctx context.Context, params *atlasexec.MigrateNewParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.File, r1 error) {
+	f.record("MigrateNew", ctx, params, opts)
+	if f.MigrateNewFunc == nil {
+		r1 = notImplemented("MigrateNew")
+		return
+	}
+	return f.MigrateNewFunc(ctx, params, opts...)
+}
+
+// MigratePush implements atlasexec.Interface.
+func (f *FakeClient) MigratePush(ctx context.Context, params *atlasexec.MigratePushParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("MigratePush", ctx, params, opts)
+	if f.MigratePushFunc == nil {
+		r1 = notImplemented("MigratePush")
+		return
+	}
+	return f.MigratePushFunc(ctx, params, opts...)
+}
+
+// MigrateRebase implements atlasexec.Interface.
+func (f *FakeClient) MigrateRebase(ctx context.Context, params *atlasexec.MigrateRebaseParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("MigrateRebase", ctx, params, opts)
+	if f.MigrateRebaseFunc == nil {
+		r0 = notImplemented("MigrateRebase")
+		return
+	}
+	return f.MigrateRebaseFunc(ctx, params, opts...)
+}
+
+// MigrateSet implements atlasexec.Interface.
+func (f *FakeClient) MigrateSet(ctx context.Context, params *atlasexec.MigrateSetParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.MigrateSet, r1 error) {
+	f.record("MigrateSet", ctx, params, opts)
+	if f.MigrateSetFunc == nil {
+		r1 = notImplemented("MigrateSet")
+		return
+	}
+	return f.MigrateSetFunc(ctx, params, opts...)
+}
+
+// MigrateStatus implements atlasexec.Interface.
+func (f *FakeClient) MigrateStatus(ctx context.Context, params *atlasexec.MigrateStatusParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.MigrateStatus, r1 error) {
+	f.record("MigrateStatus", ctx, params, opts)
+	if f.MigrateStatusFunc == nil {
+		r1 = notImplemented("MigrateStatus")
+		return
+	}
+	return f.MigrateStatusFunc(ctx, params, opts...)
+}
+
+// MigrateTest implements atlasexec.Interface.
+func (f *FakeClient) MigrateTest(ctx context.Context, params *atlasexec.MigrateTestParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("MigrateTest", ctx, params, opts)
+	if f.MigrateTestFunc == nil {
+		r1 = notImplemented("MigrateTest")
+		return
+	}
+	return f.MigrateTestFunc(ctx, params, opts...)
+}
+
+// MigrateValidate implements atlasexec.Interface.
+func (f *FakeClient) MigrateValidate(ctx context.Context, params *atlasexec.MigrateValidateParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("MigrateValidate", ctx, params, opts)
+	if f.MigrateValidateFunc == nil {
+		r0 = notImplemented("MigrateValidate")
+		return
+	}
+	return f.MigrateValidateFunc(ctx, params, opts...)
+}
+
+// SchemaApply implements atlasexec.Interface.
+func (f *FakeClient) SchemaApply(ctx context.Context, params *atlasexec.SchemaApplyParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaApply, r1 error) {
+	f.record("SchemaApply", ctx, params, opts)
+	if f.SchemaApplyFunc == nil {
+		r1 = notImplemented("SchemaApply")
+		return
+	}
+	return f.SchemaApplyFunc(ctx, params, opts...)
+}
+
+// SchemaApplySlice implements atlasexec.Interface.
+func (f *FakeClient) SchemaApplySlice(ctx context.Context, params *atlasexec.SchemaApplyParams, opts ...atlasexec.ClientOption) (r0 []*atlasexec.SchemaApply, r1 error) {
+	f.record("SchemaApplySlice", ctx, params, opts)
+	if f.SchemaApplySliceFunc == nil {
+		r1 = notImplemented("SchemaApplySlice")
+		return
+	}
+	return f.SchemaApplySliceFunc(ctx, params, opts...)
+}
+
+// SchemaClean implements atlasexec.Interface.
+func (f *FakeClient) SchemaClean(ctx context.Context, params *atlasexec.SchemaCleanParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaClean, r1 error) {
+	f.record("SchemaClean", ctx, params, opts)
+	if f.SchemaCleanFunc == nil {
+		r1 = notImplemented("SchemaClean")
+		return
+	}
+	return f.SchemaCleanFunc(ctx, params, opts...)
+}
+
+// SchemaDiff implements atlasexec.Interface.
+func (f *FakeClient) SchemaDiff(ctx context.Context, params *atlasexec.SchemaDiffParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaDiff, r1 error) {
+	f.record("SchemaDiff", ctx, params, opts)
+	if f.SchemaDiffFunc == nil {
+		r1 = notImplemented("SchemaDiff")
+		return
+	}
+	return f.SchemaDiffFunc(ctx, params, opts...)
+}
+
+// SchemaInspect implements atlasexec.Interface.
+func (f *FakeClient) SchemaInspect(ctx context.Context, params *atlasexec.SchemaInspectParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("SchemaInspect", ctx, params, opts)
+	if f.SchemaInspectFunc == nil {
+		r1 = notImplemented("SchemaInspect")
+		return
+	}
+	return f.SchemaInspectFunc(ctx, params, opts...)
+}
+
+// SchemaLint implements atlasexec.Interface.
+func (f *FakeClient) SchemaLint(ctx context.Context, params *atlasexec.SchemaLintParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaLintReport, r1 error) {
+	f.record("SchemaLint", ctx, params, opts)
+	if f.SchemaLintFunc == nil {
+		r1 = notImplemented("SchemaLint")
+		return
+	}
+	return f.SchemaLintFunc(ctx, params, opts...)
+}
+
+// SchemaPush implements atlasexec.Interface.
+func (f *FakeClient) SchemaPush(ctx context.Context, params *atlasexec.SchemaPushParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaPush, r1 error) {
+	f.record("SchemaPush", ctx, params, opts)
+	if f.SchemaPushFunc == nil {
+		r1 = notImplemented("SchemaPush")
+		return
+	}
+	return f.SchemaPushFunc(ctx, params, opts...)
+}
+
+// SchemaTest implements atlasexec.Interface.
+func (f *FakeClient) SchemaTest(ctx context.Context, params *atlasexec.SchemaTestParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("SchemaTest", ctx, params, opts)
+	if f.SchemaTestFunc == nil {
+		r1 = notImplemented("SchemaTest")
+		return
+	}
+	return f.SchemaTestFunc(ctx, params, opts...)
+}
+
+// SchemaPlan implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlan(ctx context.Context, params *atlasexec.SchemaPlanParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaPlan, r1 error) {
+	f.record("SchemaPlan", ctx, params, opts)
+	if f.SchemaPlanFunc == nil {
+		r1 = notImplemented("SchemaPlan")
+		return
+	}
+	return f.SchemaPlanFunc(ctx, params, opts...)
+}
+
+// SchemaPlanApprove implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanApprove(ctx context.Context, params *atlasexec.SchemaPlanApproveParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaPlanApprove, r1 error) {
+	f.record("SchemaPlanApprove", ctx, params, opts)
+	if f.SchemaPlanApproveFunc == nil {
+		r1 = notImplemented("SchemaPlanApprove")
+		return
+	}
+	return f.SchemaPlanApproveFunc(ctx, params, opts...)
+}
+
+// SchemaPlanLint implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanLint(ctx context.Context, params *atlasexec.SchemaPlanLintParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.SchemaPlan, r1 error) {
+	f.record("SchemaPlanLint", ctx, params, opts)
+	if f.SchemaPlanLintFunc == nil {
+		r1 = notImplemented("SchemaPlanLint")
+		return
+	}
+	return f.SchemaPlanLintFunc(ctx, params, opts...)
+}
+
+// SchemaPlanList implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanList(ctx context.Context, params *atlasexec.SchemaPlanListParams, opts ...atlasexec.ClientOption) (r0 []atlasexec.SchemaPlanFile, r1 error) {
+	f.record("SchemaPlanList", ctx, params, opts)
+	if f.SchemaPlanListFunc == nil {
+		r1 = notImplemented("SchemaPlanList")
+		return
+	}
+	return f.SchemaPlanListFunc(ctx, params, opts...)
+}
+
+// SchemaPlanPull implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanPull(ctx context.Context, params *atlasexec.SchemaPlanPullParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("SchemaPlanPull", ctx, params, opts)
+	if f.SchemaPlanPullFunc == nil {
+		r1 = notImplemented("SchemaPlanPull")
+		return
+	}
+	return f.SchemaPlanPullFunc(ctx, params, opts...)
+}
+
+// SchemaPlanPush implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanPush(ctx context.Context, params *atlasexec.SchemaPlanPushParams, opts ...atlasexec.ClientOption) (r0 string, r1 error) {
+	f.record("SchemaPlanPush", ctx, params, opts)
+	if f.SchemaPlanPushFunc == nil {
+		r1 = notImplemented("SchemaPlanPush")
+		return
+	}
+	return f.SchemaPlanPushFunc(ctx, params, opts...)
+}
+
+// SchemaPlanValidate implements atlasexec.Interface.
+func (f *FakeClient) SchemaPlanValidate(ctx context.Context, params *atlasexec.SchemaPlanValidateParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("SchemaPlanValidate", ctx, params, opts)
+	if f.SchemaPlanValidateFunc == nil {
+		r0 = notImplemented("SchemaPlanValidate")
+		return
+	}
+	return f.SchemaPlanValidateFunc(ctx, params, opts...)
+}
+
+// Login implements atlasexec.Interface.
+func (f *FakeClient) Login(ctx context.Context, params *atlasexec.LoginParams, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("Login", ctx, params, opts)
+	if f.LoginFunc == nil {
+		r0 = notImplemented("Login")
+		return
+	}
+	return f.LoginFunc(ctx, params, opts...)
+}
+
+// Logout implements atlasexec.Interface.
+func (f *FakeClient) Logout(ctx context.Context, opts ...atlasexec.ClientOption) (r0 error) {
+	f.record("Logout", ctx, opts)
+	if f.LogoutFunc == nil {
+		r0 = notImplemented("Logout")
+		return
+	}
+	return f.LogoutFunc(ctx, opts...)
+}
+
+// WhoAmI implements atlasexec.Interface.
+func (f *FakeClient) WhoAmI(ctx context.Context, params *atlasexec.WhoAmIParams, opts ...atlasexec.ClientOption) (r0 *atlasexec.WhoAmI, r1 error) {
+	f.record("WhoAmI", ctx, params, opts)
+	if f.WhoAmIFunc == nil {
+		r1 = notImplemented("WhoAmI")
+		return
+	}
+	return f.WhoAmIFunc(ctx, params, opts...)
+}
+
+// Copilot implements atlasexec.Interface.
+func (f *FakeClient) Copilot(ctx context.Context, params *atlasexec.CopilotParams, opts ...atlasexec.ClientOption) (r0 atlasexec.Copilot, r1 error) {
+	f.record("Copilot", ctx, params, opts)
+	if f.CopilotFunc == nil {
+		r1 = notImplemented("Copilot")
+		return
+	}
+	return f.CopilotFunc(ctx, params, opts...)
+}
+
+// CopilotStream implements atlasexec.Interface.
+func (f *FakeClient) CopilotStream(ctx context.Context, params *atlasexec.CopilotParams, opts ...atlasexec.ClientOption) (r0 atlasexec.Stream[*atlasexec.CopilotMessage], r1 error) {
+	f.record("CopilotStream", ctx, params, opts)
+	if f.CopilotStreamFunc == nil {
+		r1 = notImplemented("CopilotStream")
+		return
+	}
+	return f.CopilotStreamFunc(ctx, params, opts...)
+}
+
+// Version implements atlasexec.Interface.
+func (f *FakeClient) Version(ctx context.Context, opts ...atlasexec.ClientOption) (r0 *atlasexec.Version, r1 error) {
+	f.record("Version", ctx, opts)
+	if f.VersionFunc == nil {
+		r1 = notImplemented("Version")
+		return
+	}
+	return f.VersionFunc(ctx, opts...)
+}
+
+// Run implements atlasexec.Interface.
+func (f *FakeClient) Run(ctx context.Context, args ...string) (r0 string, r1 error) {
+	f.record("Run", ctx, args)
+	if f.RunFunc == nil {
+		r1 = notImplemented("Run")
+		return
+	}
+	return f.RunFunc(ctx, args...)
+}
@@ -0,0 +1,50 @@
+package atlasexectest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type (
+	// FakeCall describes a call to one of the methods of the FakeClient.
+	FakeCall struct {
+		Method string // Name of the method, e.g. "MigrateApply".
+		Args   []any  // Arguments of the call, including the context and options.
+	}
+	// fakeCalls records the calls of the FakeClient.
+	fakeCalls struct {
+		mu    sync.Mutex
+		calls []FakeCall
+	}
+)
+
+// Calls returns the calls made to the FakeClient, in order.
+func (f *fakeCalls) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// CallsOf returns the calls made to the given method of the FakeClient.
+func (f *fakeCalls) CallsOf(method string) []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []FakeCall
+	for _, c := range f.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func (f *fakeCalls) record(method string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, FakeCall{Method: method, Args: args})
+}
+
+func notImplemented(method string) error {
+	return fmt.Errorf("atlasexectest: FakeClient.%s is not implemented", method)
+}
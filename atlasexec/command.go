@@ -0,0 +1,113 @@
+package atlasexec
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Params is implemented by the parameters of the commands,
+// for example, *MigrateApplyParams or *SchemaPlanParams.
+type Params interface {
+	// AsArgs returns the parameters as arguments of the atlas-cli.
+	AsArgs() ([]string, error)
+	// Validate reports if the parameters are invalid.
+	Validate() error
+}
+
+// Command returns the command the Client runs for the given parameters, without running
+// it. The command is configured with the arguments, the environment and the working
+// directory of the Client, and can be used to preview a change or to reproduce a failure
+// locally. Use the CommandString method to display it with secrets masked. Note that the timeout,
+// hooks and retry policy of the Client are not applied to the returned command.
+//
+//	cmd, err := c.Command(ctx, &atlasexec.MigrateApplyParams{Env: "prod"})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(c.CommandString(cmd))
+func (c *Client) Command(ctx context.Context, params Params, opts ...ClientOption) (*exec.Cmd, error) {
+	c = c.With(opts...)
+	params = applyDefaultsTo(c, params)
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	var e ProcessExecutor
+	switch ex := c.executor.(type) {
+	case ProcessExecutor:
+		e = ex
+	case *ProcessExecutor:
+		e = *ex
+	}
+	inv, err := c.invocation(args)
+	if err != nil {
+		return nil, err
+	}
+	return e.command(ctx, inv), nil
+}
+
+// CommandString returns the command line of the given command, quoted for POSIX
+// shells, with credentials masked. Besides URL passwords and tokens, the values of
+// the secret variables of the Client and well-known secret environment variables of
+// the command are masked. See WithSecretVars for details.
+func (c *Client) CommandString(cmd *exec.Cmd) string {
+	if len(cmd.Args) == 0 {
+		return shellQuote(cmd.Path)
+	}
+	r := c.redactor(cmd.Args[1:], cmd.Env)
+	args := append([]string{cmd.Args[0]}, r.args(cmd.Args[1:])...)
+	for i, a := range args {
+		args[i] = shellQuote(a)
+	}
+	return strings.Join(args, " ")
+}
+
+var reShellSafe = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes s for POSIX shells, if needed.
+func shellQuote(s string) string {
+	if reShellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+var _ = []Params{
+	(*LoginParams)(nil),
+	(*WhoAmIParams)(nil),
+	(*CopilotParams)(nil),
+	(*MigrateApplyParams)(nil),
+	(*MigrateDownParams)(nil),
+	(*MigratePushParams)(nil),
+	(*MigrateLintParams)(nil),
+	(*MigrateHashParams)(nil),
+	(*MigrateRebaseParams)(nil),
+	(*MigrateTestParams)(nil),
+	(*MigrateStatusParams)(nil),
+	(*MigrateDiffParams)(nil),
+	(*MigrateNewParams)(nil),
+	(*MigrateImportParams)(nil),
+	(*MigrateCheckpointParams)(nil),
+	(*MigrateSetParams)(nil),
+	(*MigrateValidateParams)(nil),
+	(*MigrateLsParams)(nil),
+	(*SchemaPushParams)(nil),
+	(*SchemaApplyParams)(nil),
+	(*SchemaInspectParams)(nil),
+	(*SchemaDiffParams)(nil),
+	(*SchemaTestParams)(nil),
+	(*SchemaPlanParams)(nil),
+	(*SchemaPlanListParams)(nil),
+	(*SchemaPlanPushParams)(nil),
+	(*SchemaPlanPullParams)(nil),
+	(*SchemaPlanLintParams)(nil),
+	(*SchemaPlanValidateParams)(nil),
+	(*SchemaPlanApproveParams)(nil),
+	(*SchemaCleanParams)(nil),
+	(*SchemaLintParams)(nil),
+}
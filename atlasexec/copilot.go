@@ -46,20 +46,33 @@ type (
 )
 
 // Copilot executes a one-shot Copilot session with the provided options.
-func (c *Client) Copilot(ctx context.Context, params *CopilotParams) (Copilot, error) {
-	args := []string{"copilot", "-q", params.Prompt}
-	if params.Session != "" {
-		args = append(args, "-r", params.Session)
-	}
-	if params.FSWrite != "" {
-		args = append(args, "-p", "fs.write="+params.FSWrite)
+func (c *Client) Copilot(ctx context.Context, params *CopilotParams, opts ...ClientOption) (Copilot, error) {
+	c = c.With(opts...)
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	if params.FSDelete != "" {
-		args = append(args, "-p", "fs.delete="+params.FSDelete)
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
 	}
 	return jsonDecode[CopilotMessage](c.runCommand(ctx, args))
 }
 
+// AsArgs returns the parameters as arguments.
+func (p *CopilotParams) AsArgs() ([]string, error) {
+	args := []string{"copilot", "-q", p.Prompt}
+	if p.Session != "" {
+		args = append(args, "-r", p.Session)
+	}
+	if p.FSWrite != "" {
+		args = append(args, "-p", "fs.write="+p.FSWrite)
+	}
+	if p.FSDelete != "" {
+		args = append(args, "-p", "fs.delete="+p.FSDelete)
+	}
+	return args, nil
+}
+
 type copilotStream struct {
 	s   Stream[string]
 	cur *CopilotMessage
@@ -101,19 +114,22 @@ func (s *copilotStream) Err() error {
 	return s.s.Err()
 }
 
+// Close stops the Copilot session and returns its final error.
+func (s *copilotStream) Close() error {
+	return s.s.Close()
+}
+
 var _ Stream[*CopilotMessage] = (*copilotStream)(nil)
 
 // CopilotStream executes a one-shot Copilot session, streaming the result.
-func (c *Client) CopilotStream(ctx context.Context, params *CopilotParams) (Stream[*CopilotMessage], error) {
-	args := []string{"copilot", "-q", params.Prompt}
-	if params.Session != "" {
-		args = append(args, "-r", params.Session)
-	}
-	if params.FSWrite != "" {
-		args = append(args, "-p", "fs.write="+params.FSWrite)
+func (c *Client) CopilotStream(ctx context.Context, params *CopilotParams, opts ...ClientOption) (Stream[*CopilotMessage], error) {
+	c = c.With(opts...)
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	if params.FSDelete != "" {
-		args = append(args, "-p", "fs.delete="+params.FSDelete)
+	args, err := params.AsArgs()
+	if err != nil {
+		return nil, err
 	}
 	s, err := c.runCommandStream(ctx, args)
 	if err != nil {
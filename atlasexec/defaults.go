@@ -0,0 +1,106 @@
+package atlasexec
+
+import (
+	"maps"
+	"reflect"
+)
+
+// Defaults holds parameters that are shared by the commands of a Client,
+// and are used when they are not set in the parameters of the command.
+//
+// The precedence rules are:
+//   - ConfigURL and Env are used if the parameter of the command is empty.
+//   - Vars are merged with the variables of the command, which take precedence
+//     on conflicts. If the command uses a custom VarArgs implementation, that is,
+//     not Vars2 or Vars, its variables are used as-is and the defaults are ignored.
+//   - RunContext and DeployRunContext are used if the Context of the command is nil.
+//     The commands 'migrate apply' and 'migrate down' use DeployRunContext, and the
+//     rest of the commands that accept a context use RunContext.
+type Defaults struct {
+	ConfigURL        string
+	Env              string
+	Vars             Vars2
+	RunContext       *RunContext
+	DeployRunContext *DeployRunContext
+}
+
+// WithDefaults configures the default parameters of the commands.
+// It replaces the defaults set by previous calls to WithDefaults.
+//
+//	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithDefaults(atlasexec.Defaults{
+//		ConfigURL: "file://atlas.hcl",
+//		Env:       "prod",
+//	}))
+func WithDefaults(d Defaults) ClientOption {
+	return func(c *Client) {
+		d.Vars = maps.Clone(d.Vars)
+		c.defaults = &d
+	}
+}
+
+var (
+	typeVarArgs          = reflect.TypeFor[VarArgs]()
+	typeRunContext       = reflect.TypeFor[*RunContext]()
+	typeDeployRunContext = reflect.TypeFor[*DeployRunContext]()
+)
+
+// applyDefaults returns a copy of the given parameters with
+// the defaults of the client applied. See Defaults for details.
+func applyDefaults[P any](c *Client, params *P) *P {
+	if c.defaults == nil || params == nil {
+		return params
+	}
+	cp := *params
+	c.defaults.apply(reflect.ValueOf(&cp).Elem())
+	return &cp
+}
+
+// applyDefaultsTo is like applyDefaults, but accepts any Params
+// implementation that is a pointer to a struct.
+func applyDefaultsTo(c *Client, params Params) Params {
+	v := reflect.ValueOf(params)
+	if c.defaults == nil || v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return params
+	}
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+	c.defaults.apply(cp.Elem())
+	return cp.Interface().(Params)
+}
+
+// apply sets the defaults on the fields of the given parameters struct.
+func (d *Defaults) apply(v reflect.Value) {
+	for name, value := range map[string]string{"ConfigURL": d.ConfigURL, "Env": d.Env} {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.String() == "" {
+			f.SetString(value)
+		}
+	}
+	if f := v.FieldByName("Vars"); f.IsValid() && f.Type() == typeVarArgs && len(d.Vars) > 0 {
+		f.Set(reflect.ValueOf(mergeVars(d.Vars, f.Interface())))
+	}
+	if f := v.FieldByName("Context"); f.IsValid() && f.Kind() == reflect.Pointer && f.IsNil() {
+		switch {
+		case f.Type() == typeRunContext && d.RunContext != nil:
+			f.Set(reflect.ValueOf(d.RunContext))
+		case f.Type() == typeDeployRunContext && d.DeployRunContext != nil:
+			f.Set(reflect.ValueOf(d.DeployRunContext))
+		}
+	}
+}
+
+// mergeVars merges the default variables with the ones of a command.
+func mergeVars(defaults Vars2, vars any) VarArgs {
+	merged := maps.Clone(defaults)
+	switch vars := vars.(type) {
+	case nil:
+	case Vars2:
+		maps.Copy(merged, vars)
+	case Vars:
+		for k, v := range vars {
+			merged[k] = v
+		}
+	default:
+		return vars.(VarArgs)
+	}
+	return merged
+}
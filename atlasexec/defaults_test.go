@@ -0,0 +1,221 @@
+package atlasexec_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaults(t *testing.T) {
+	ctx := context.Background()
+	defaults := atlasexec.WithDefaults(atlasexec.Defaults{
+		ConfigURL:        "file://atlas.hcl",
+		Env:              "prod",
+		Vars:             atlasexec.Vars2{"a": "1"},
+		RunContext:       &atlasexec.RunContext{Repo: "ariga/app"},
+		DeployRunContext: &atlasexec.DeployRunContext{TriggerVersion: "v1"},
+	})
+	const (
+		none = iota
+		run
+		deploy
+	)
+	for _, tt := range []struct {
+		name    string
+		run     func(atlasexec.Interface) error
+		context int // Type of the run context the command accepts.
+	}{
+		{"migrate apply", func(c atlasexec.Interface) error {
+			_, err := c.MigrateApply(ctx, &atlasexec.MigrateApplyParams{})
+			return err
+		}, deploy},
+		{"migrate apply stream", func(c atlasexec.Interface) error {
+			s, err := c.MigrateApplyStream(ctx, &atlasexec.MigrateApplyParams{})
+			if err != nil {
+				return err
+			}
+			return s.Close()
+		}, deploy},
+		{"migrate down", func(c atlasexec.Interface) error {
+			_, err := c.MigrateDown(ctx, &atlasexec.MigrateDownParams{})
+			return err
+		}, deploy},
+		{"migrate push", func(c atlasexec.Interface) error {
+			_, err := c.MigratePush(ctx, &atlasexec.MigratePushParams{Name: "app"})
+			return err
+		}, run},
+		{"migrate lint", func(c atlasexec.Interface) error {
+			_, err := c.MigrateLint(ctx, &atlasexec.MigrateLintParams{})
+			return err
+		}, run},
+		{"migrate hash", func(c atlasexec.Interface) error {
+			return c.MigrateHash(ctx, &atlasexec.MigrateHashParams{})
+		}, none},
+		{"migrate rebase", func(c atlasexec.Interface) error {
+			return c.MigrateRebase(ctx, &atlasexec.MigrateRebaseParams{Files: []string{"1.sql"}})
+		}, none},
+		{"migrate test", func(c atlasexec.Interface) error {
+			_, err := c.MigrateTest(ctx, &atlasexec.MigrateTestParams{})
+			return err
+		}, run},
+		{"migrate status", func(c atlasexec.Interface) error {
+			_, err := c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{})
+			return err
+		}, none},
+		{"migrate diff", func(c atlasexec.Interface) error {
+			_, err := c.MigrateDiff(ctx, &atlasexec.MigrateDiffParams{})
+			return err
+		}, none},
+		{"schema push", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPush(ctx, &atlasexec.SchemaPushParams{Name: "app"})
+			return err
+		}, run},
+		{"schema apply", func(c atlasexec.Interface) error {
+			_, err := c.SchemaApply(ctx, &atlasexec.SchemaApplyParams{})
+			return err
+		}, none},
+		{"schema inspect", func(c atlasexec.Interface) error {
+			_, err := c.SchemaInspect(ctx, &atlasexec.SchemaInspectParams{})
+			return err
+		}, none},
+		{"schema test", func(c atlasexec.Interface) error {
+			_, err := c.SchemaTest(ctx, &atlasexec.SchemaTestParams{})
+			return err
+		}, none},
+		{"schema plan", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlan(ctx, &atlasexec.SchemaPlanParams{})
+			return err
+		}, run},
+		{"schema plan list", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlanList(ctx, &atlasexec.SchemaPlanListParams{})
+			return err
+		}, run},
+		{"schema plan push", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlanPush(ctx, &atlasexec.SchemaPlanPushParams{File: "1.plan.hcl"})
+			return err
+		}, run},
+		{"schema plan pull", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlanPull(ctx, &atlasexec.SchemaPlanPullParams{URL: "atlas://app/plans/1"})
+			return err
+		}, none},
+		{"schema plan lint", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlanLint(ctx, &atlasexec.SchemaPlanLintParams{File: "1.plan.hcl"})
+			return err
+		}, run},
+		{"schema plan validate", func(c atlasexec.Interface) error {
+			return c.SchemaPlanValidate(ctx, &atlasexec.SchemaPlanValidateParams{File: "1.plan.hcl"})
+		}, run},
+		{"schema plan approve", func(c atlasexec.Interface) error {
+			_, err := c.SchemaPlanApprove(ctx, &atlasexec.SchemaPlanApproveParams{URL: "atlas://app/plans/1"})
+			return err
+		}, none},
+		{"schema clean", func(c atlasexec.Interface) error {
+			_, err := c.SchemaClean(ctx, &atlasexec.SchemaCleanParams{})
+			return err
+		}, none},
+		{"schema lint", func(c atlasexec.Interface) error {
+			_, err := c.SchemaLint(ctx, &atlasexec.SchemaLintParams{})
+			return err
+		}, none},
+		{"whoami", func(c atlasexec.Interface) error {
+			_, err := c.WhoAmI(ctx, &atlasexec.WhoAmIParams{})
+			return err
+		}, none},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ex := atlasexectest.NewClient(t, defaults)
+			ex.OnMatch(func([]string) bool { return true }).Stdout("{}")
+			_ = tt.run(c)
+			invs := ex.Invocations()
+			require.Len(t, invs, 1)
+			args := invs[0].Args
+			requireFlag(t, args, "--config", "file://atlas.hcl")
+			requireFlag(t, args, "--env", "prod")
+			requireFlag(t, args, "--var", "a=1")
+			switch tt.context {
+			case run:
+				requireFlag(t, args, "--context", `{"repo":"ariga/app"}`)
+			case deploy:
+				requireFlag(t, args, "--context", `{"triggerVersion":"v1"}`)
+			default:
+				require.NotContains(t, args, "--context")
+			}
+		})
+	}
+}
+
+func TestWithDefaults_Precedence(t *testing.T) {
+	ctx := context.Background()
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithDefaults(atlasexec.Defaults{
+		ConfigURL:  "file://atlas.hcl",
+		Env:        "prod",
+		Vars:       atlasexec.Vars2{"a": "1", "b": "2"},
+		RunContext: &atlasexec.RunContext{Repo: "ariga/app"},
+	}))
+	ex.OnMatch(func([]string) bool { return true }).Stdout("{}")
+
+	// Parameters take precedence over the defaults, and variables are merged.
+	params := &atlasexec.MigrateLintParams{
+		ConfigURL: "file://other.hcl",
+		Env:       "dev",
+		Vars:      atlasexec.Vars{"b": "3"},
+		Context:   &atlasexec.RunContext{Repo: "ariga/other"},
+	}
+	_, _ = c.MigrateLint(ctx, params)
+	args := ex.Invocations()[0].Args
+	requireFlag(t, args, "--config", "file://other.hcl")
+	requireFlag(t, args, "--env", "dev")
+	requireFlag(t, args, "--var", "a=1")
+	requireFlag(t, args, "--var", "b=3")
+	require.NotContains(t, args, "b=2")
+	requireFlag(t, args, "--context", `{"repo":"ariga/other"}`)
+	require.Equal(t, atlasexec.Vars{"b": "3"}, params.Vars, "parameters are not modified")
+
+	// Custom VarArgs are used as-is.
+	_, _ = c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{Vars: customVars{"--var", "c=4"}})
+	args = ex.Invocations()[1].Args
+	requireFlag(t, args, "--var", "c=4")
+	require.NotContains(t, args, "a=1")
+
+	// Defaults can be overridden per command, or reset.
+	_, _ = c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{}, atlasexec.WithDefaults(atlasexec.Defaults{Env: "staging"}))
+	args = ex.Invocations()[2].Args
+	requireFlag(t, args, "--env", "staging")
+	require.NotContains(t, args, "--config")
+	require.NotContains(t, args, "--var")
+
+	// User-defined parameters with a non-pointer Context are accepted.
+	cmd, err := c.Command(ctx, &customParams{Context: "ci"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"custom", "--env", "prod"}, cmd.Args[1:])
+}
+
+type customParams struct {
+	Env     string
+	Context string
+}
+
+func (p *customParams) AsArgs() ([]string, error) {
+	return []string{"custom", "--env", p.Env}, nil
+}
+
+func (p *customParams) Validate() error { return nil }
+
+type customVars []string
+
+func (v customVars) AsArgs() []string { return v }
+
+// requireFlag requires the arguments to contain the given flag and value.
+func requireFlag(t *testing.T, args []string, flag, value string) {
+	t.Helper()
+	for i := range args[:max(len(args)-1, 0)] {
+		if args[i] == flag && args[i+1] == value {
+			return
+		}
+	}
+	require.Failf(t, "flag not found", "%s %s not found in %q", flag, value, slices.Clone(args))
+}
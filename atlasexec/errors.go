@@ -0,0 +1,171 @@
+package atlasexec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Well-known failures of the atlas-cli. Errors returned by the Client
+// can be matched against them using errors.Is. For example:
+//
+//	if errors.Is(err, atlasexec.ErrLockTimeout) {
+//		// Retry later.
+//	}
+var (
+	// ErrChecksumMismatch is returned when the atlas.sum file does not match
+	// the content of the migration directory.
+	ErrChecksumMismatch = errors.New("atlasexec: checksum mismatch")
+	// ErrDirtyDatabase is returned when the connected database is not clean,
+	// i.e. it contains resources that are not tracked by the revisions table.
+	ErrDirtyDatabase = errors.New("atlasexec: connected database is not clean")
+	// ErrLockTimeout is returned when the database lock could not be acquired,
+	// usually because another migration is running at the same time.
+	ErrLockTimeout = errors.New("atlasexec: acquiring database lock timed out")
+	// ErrUnknownEnv is returned when the requested env is not defined in the project file.
+	ErrUnknownEnv = errors.New("atlasexec: env not defined in project file")
+	// ErrHCLSyntax is returned when an HCL file could not be parsed or evaluated.
+	// Use errors.As with HCLError to get the position of the failure.
+	ErrHCLSyntax = errors.New("atlasexec: invalid HCL file")
+	// ErrConnectionRefused is returned when the database refused the connection.
+	ErrConnectionRefused = errors.New("atlasexec: database connection refused")
+	// ErrAuthFailed is returned when the database rejected the credentials.
+	ErrAuthFailed = errors.New("atlasexec: database authentication failed")
+	// ErrCanceled is returned when the context of the command was done before the
+	// atlas-cli exited. The error also matches the cause of the context, e.g.
+	// context.Canceled. For 'migrate apply', the returned MigrateApplyError holds
+	// the migrations that were applied before the command was stopped.
+	ErrCanceled = errors.New("atlasexec: command canceled")
+)
+
+// HCLError describes a failure in parsing or evaluating an HCL file.
+type HCLError struct {
+	File         string // Name of the file.
+	Line, Column int    // Position of the failure.
+	Summary      string // Summary of the failure.
+	Detail       string // Detailed description of the failure, if exists.
+}
+
+var (
+	reUnknownEnv = regexp.MustCompile(`env "[^"]*" not defined in (?:config|project) file`)
+	// The format of hcl.Diagnostic.Error(), e.g. "atlas.hcl:3,5-6: Summary; Detail".
+	reHCLDiag = regexp.MustCompile(`(?m)([^\s:]+\.hcl):(\d+),(\d+)(?:-(?:\d+,)?\d+)?: ([^;\n]+)(?:; ([^\n]*))?`)
+	// errClasses maps error messages to their well-known failures.
+	errClasses = []struct {
+		err   error
+		match func(string) bool
+	}{
+		{ErrChecksumMismatch, contains(migrate.ErrChecksumMismatch.Error())},
+		{ErrDirtyDatabase, contains("connected database is not clean")},
+		{ErrLockTimeout, contains(schema.ErrLocked.Error(), "acquiring database lock")},
+		{ErrUnknownEnv, reUnknownEnv.MatchString},
+		{ErrConnectionRefused, contains("connection refused")},
+		{ErrAuthFailed, contains(
+			"password authentication failed", // PostgreSQL
+			"Access denied for user",         // MySQL
+			"Login failed for user",          // SQL Server
+		)},
+	}
+)
+
+// classifyErr returns the well-known failure described by the given
+// error message, or nil if the message is not recognized.
+func classifyErr(msg string) error {
+	if m := reHCLDiag.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		return &HCLError{File: m[1], Line: line, Column: col, Summary: m[4], Detail: m[5]}
+	}
+	for _, c := range errClasses {
+		if c.match(msg) {
+			return c.err
+		}
+	}
+	return nil
+}
+
+// classify returns the well-known failure described by the stderr of a command,
+// or by its stdout in case the error was reported in the command output.
+func classify(stderr, stdout string) error {
+	if class := classifyErr(stderr); class != nil {
+		return class
+	}
+	return classifyErr(stdout)
+}
+
+func contains(subs ...string) func(string) bool {
+	return func(s string) bool {
+		for _, sub := range subs {
+			if strings.Contains(s, sub) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Error implements the error interface.
+func (e *HCLError) Error() string {
+	msg := fmt.Sprintf("atlasexec: %s:%d,%d: %s", e.File, e.Line, e.Column, e.Summary)
+	if e.Detail != "" {
+		msg += "; " + e.Detail
+	}
+	return msg
+}
+
+// Is reports whether the target is ErrHCLSyntax.
+func (e *HCLError) Is(target error) bool {
+	return target == ErrHCLSyntax
+}
+
+// Is reports whether the error matches one of the well-known failures, like ErrLockTimeout.
+func (e *Error) Is(target error) bool {
+	return e.class != nil && errors.Is(e.class, target)
+}
+
+// As finds the first error in the classified failure of e that matches target, like HCLError.
+func (e *Error) As(target any) bool {
+	return e.class != nil && errors.As(e.class, target)
+}
+
+// Is reports whether the error matches one of the well-known failures, like ErrLockTimeout.
+func (e *MigrateApplyError) Is(target error) bool {
+	return errors.Is(classifyErr(e.Error()), target)
+}
+
+// As finds the first error in the classified failure of e that matches target, like HCLError.
+func (e *MigrateApplyError) As(target any) bool {
+	class := classifyErr(e.Error())
+	return class != nil && errors.As(class, target)
+}
+
+// Unwrap returns the underlying CLI error, if exists.
+func (e *MigrateApplyError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error matches one of the well-known failures, like ErrLockTimeout.
+func (e *SchemaApplyError) Is(target error) bool {
+	return errors.Is(classifyErr(e.Error()), target)
+}
+
+// As finds the first error in the classified failure of e that matches target, like HCLError.
+func (e *SchemaApplyError) As(target any) bool {
+	class := classifyErr(e.Error())
+	return class != nil && errors.As(class, target)
+}
+
+// Unwrap returns the underlying CLI error, if exists.
+func (e *SchemaApplyError) Unwrap() error {
+	return e.err
+}
+
+// Unwrap returns the underlying CLI error.
+func (e *MigrateValidateError) Unwrap() error {
+	return e.err
+}
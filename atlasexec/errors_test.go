@@ -0,0 +1,110 @@
+package atlasexec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_Classify(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		stderr string
+		want   error
+	}{
+		{
+			name:   "checksum",
+			stderr: "Error: checksum mismatch",
+			want:   atlasexec.ErrChecksumMismatch,
+		},
+		{
+			name:   "dirty",
+			stderr: `Error: sql/migrate: connected database is not clean: found table "users"`,
+			want:   atlasexec.ErrDirtyDatabase,
+		},
+		{
+			name:   "lock",
+			stderr: "Error: acquiring database lock: sql/schema: lock is held by other session",
+			want:   atlasexec.ErrLockTimeout,
+		},
+		{
+			name:   "env",
+			stderr: `Error: env "prod" not defined in project file`,
+			want:   atlasexec.ErrUnknownEnv,
+		},
+		{
+			name:   "refused",
+			stderr: "Error: dial tcp 127.0.0.1:5432: connect: connection refused",
+			want:   atlasexec.ErrConnectionRefused,
+		},
+		{
+			name:   "postgres auth",
+			stderr: `Error: pq: password authentication failed for user "root"`,
+			want:   atlasexec.ErrAuthFailed,
+		},
+		{
+			name:   "mysql auth",
+			stderr: "Error: Error 1045 (28000): Access denied for user 'root'@'172.17.0.1' (using password: YES)",
+			want:   atlasexec.ErrAuthFailed,
+		},
+		{
+			name:   "hcl",
+			stderr: `Error: atlas.hcl:3,5-9: Unsupported argument; An argument named "urls" is not expected here.`,
+			want:   atlasexec.ErrHCLSyntax,
+		},
+		{
+			name:   "unknown",
+			stderr: "Error: something went wrong",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ex := atlasexectest.NewClient(t)
+			ex.On("migrate", "status", "--format", "{{ json . }}").Stderr(tt.stderr)
+			_, err := c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{})
+			require.Error(t, err)
+			var aerr *atlasexec.Error
+			require.ErrorAs(t, err, &aerr)
+			require.Equal(t, tt.stderr, aerr.Stderr)
+			for _, sentinel := range []error{
+				atlasexec.ErrChecksumMismatch, atlasexec.ErrDirtyDatabase, atlasexec.ErrLockTimeout,
+				atlasexec.ErrUnknownEnv, atlasexec.ErrHCLSyntax, atlasexec.ErrConnectionRefused,
+				atlasexec.ErrAuthFailed,
+			} {
+				require.Equal(t, sentinel == tt.want, errors.Is(err, sentinel), sentinel.Error())
+			}
+		})
+	}
+}
+
+func TestError_HCL(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("schema", "inspect", "--url", "file://schema.hcl").
+		Stderr("Error: schema.hcl:12,3-3,8: Missing required argument; The argument \"type\" is required.")
+	_, err := c.SchemaInspect(context.Background(), &atlasexec.SchemaInspectParams{URL: "file://schema.hcl"})
+	var herr *atlasexec.HCLError
+	require.ErrorAs(t, err, &herr)
+	require.Equal(t, &atlasexec.HCLError{
+		File:    "schema.hcl",
+		Line:    12,
+		Column:  3,
+		Summary: "Missing required argument",
+		Detail:  `The argument "type" is required.`,
+	}, herr)
+	require.ErrorIs(t, err, atlasexec.ErrHCLSyntax)
+}
+
+func TestError_MigrateApply(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "apply", "--format", "{{ json . }}").
+		Stdout(`{"Error":"acquiring database lock: sql/schema: lock is held by other session"}`).
+		ExitCode(1)
+	_, err := c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{})
+	var merr *atlasexec.MigrateApplyError
+	require.ErrorAs(t, err, &merr)
+	require.ErrorIs(t, err, atlasexec.ErrLockTimeout)
+	require.NotErrorIs(t, err, atlasexec.ErrDirtyDatabase)
+}
@@ -0,0 +1,83 @@
+package atlasexec
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+type (
+	// Executor runs the atlas-cli on behalf of the Client. The default implementation,
+	// ProcessExecutor, starts the binary found at the exec path of the Client. Custom
+	// implementations can be used to fake, sandbox or record the executions.
+	Executor interface {
+		// Exec runs the given invocation and blocks until it completes.
+		// A non-zero exit status should be reported using an error that
+		// implements the ExitCode() int method, like *exec.ExitError.
+		Exec(context.Context, *Invocation) error
+	}
+	// Invocation describes a single execution of the atlas-cli.
+	Invocation struct {
+		Path   string    // Path to the atlas-cli binary.
+		Args   []string  // Arguments, excluding the binary path.
+		Env    []string  // Environment variables in the form of "key=value".
+		Dir    string    // Working directory. If empty, the current directory is used.
+		Stdout io.Writer // Writer for the standard output of the command.
+		Stderr io.Writer // Writer for the standard error of the command.
+	}
+	// ProcessExecutor is the default Executor. It runs the atlas-cli
+	// as a child process using the os/exec package.
+	ProcessExecutor struct {
+		// Signal is sent to the process when the context is done, allowing the
+		// atlas-cli to stop gracefully, e.g. after the current migration file.
+		// If nil, the process is killed immediately.
+		Signal os.Signal
+		// GracePeriod is the time the process is given to exit after Signal was
+		// sent, before it is killed. Defaults to 10 seconds.
+		GracePeriod time.Duration
+	}
+)
+
+// WithGracefulCancel configures the Client to interrupt the atlas-cli process with
+// SIGINT when the context of a command is done, instead of killing it. The process
+// is killed if it did not exit within the given grace period. This allows commands
+// like 'migrate apply' to report their partial results instead of leaving the
+// database in an unknown state. The option has no effect on custom executors.
+// On Windows, where interrupts are not supported, the process is killed after
+// the grace period.
+func WithGracefulCancel(grace time.Duration) ClientOption {
+	return func(c *Client) {
+		switch c.executor.(type) {
+		case ProcessExecutor, *ProcessExecutor, nil:
+			c.executor = ProcessExecutor{Signal: os.Interrupt, GracePeriod: grace}
+		}
+	}
+}
+
+// Exec implements the Executor interface.
+func (e ProcessExecutor) Exec(ctx context.Context, inv *Invocation) error {
+	return e.command(ctx, inv).Run()
+}
+
+// command returns the exec.Cmd that runs the given invocation.
+func (e ProcessExecutor) command(ctx context.Context, inv *Invocation) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, inv.Path, inv.Args...)
+	if e.Signal != nil {
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(e.Signal)
+		}
+		cmd.WaitDelay = e.GracePeriod
+		if cmd.WaitDelay <= 0 {
+			cmd.WaitDelay = 10 * time.Second
+		}
+	}
+	cmd.Dir = inv.Dir
+	cmd.Env = inv.Env
+	cmd.Stdout = inv.Stdout
+	cmd.Stderr = inv.Stderr
+	return cmd
+}
+
+var _ Executor = (*ProcessExecutor)(nil)
@@ -0,0 +1,135 @@
+package atlasexec
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type (
+	// Hook holds callbacks that are called around every atlas-cli invocation made by
+	// the Client. Hooks are useful for logging, collecting metrics or auditing commands.
+	Hook struct {
+		// Before is called before the command is executed. If it returns a non-nil
+		// context, it is used to execute the command and passed to the After callback
+		// of the same hook, for example, to propagate a tracing span.
+		Before func(context.Context, *CommandInfo) context.Context
+		// After is called after the command is completed.
+		After func(context.Context, *CommandInfo, *CommandResult)
+	}
+	// CommandInfo describes an atlas-cli invocation.
+	CommandInfo struct {
+		Name string   // Name of the (sub)command, e.g. "migrate apply".
+		Args []string // Arguments of the command, with secrets redacted.
+		Dir  string   // Working directory of the command.
+	}
+	// CommandResult describes the outcome of an atlas-cli invocation.
+	CommandResult struct {
+		Duration time.Duration // Execution time of the command.
+		ExitCode int           // Exit code of the command, or -1 if it is unknown.
+		Err      error         // Error returned by the Client, if any.
+	}
+)
+
+// WithHooks configures the Client to call the given hooks around every command.
+// The Before callbacks are called in the given order, and the After callbacks in reverse.
+func WithHooks(hooks ...Hook) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// NewLogHook returns a Hook that logs every command executed by the Client,
+// using the given logger. Failed commands are logged at the error level.
+func NewLogHook(l *slog.Logger) Hook {
+	return Hook{
+		After: func(ctx context.Context, info *CommandInfo, r *CommandResult) {
+			attrs := []slog.Attr{
+				slog.String("command", info.Name),
+				slog.Any("args", info.Args),
+				slog.String("dir", info.Dir),
+				slog.Duration("duration", r.Duration),
+				slog.Int("exit_code", r.ExitCode),
+			}
+			if r.Err != nil {
+				l.LogAttrs(ctx, slog.LevelError, "atlas command failed", append(attrs, slog.String("error", r.Err.Error()))...)
+				return
+			}
+			l.LogAttrs(ctx, slog.LevelInfo, "atlas command executed", attrs...)
+		},
+	}
+}
+
+// exec runs the invocation wrapped with the client hooks,
+// and returns the error as reported by runErr.
+func (c *Client) exec(ctx context.Context, inv *Invocation, stdout, stderr interface{ String() string }) error {
+	rd := c.redactor(inv.Args, inv.Env)
+	if len(c.hooks) == 0 {
+		return c.runErr(ctx, c.executor.Exec(ctx, inv), rd, stdout, stderr)
+	}
+	var (
+		ctxs = make([]context.Context, len(c.hooks))
+		info = &CommandInfo{
+			Name: commandName(inv.Args),
+			Args: rd.args(inv.Args),
+			Dir:  inv.Dir,
+		}
+	)
+	for i, h := range c.hooks {
+		if h.Before != nil {
+			if hctx := h.Before(ctx, info); hctx != nil {
+				ctx = hctx
+			}
+		}
+		ctxs[i] = ctx
+	}
+	start := time.Now()
+	exitErr := c.executor.Exec(ctx, inv)
+	r := &CommandResult{
+		Duration: time.Since(start),
+		ExitCode: exitCode(exitErr),
+		Err:      c.runErr(ctx, exitErr, rd, stdout, stderr),
+	}
+	for i := len(c.hooks) - 1; i >= 0; i-- {
+		if h := c.hooks[i]; h.After != nil {
+			h.After(ctxs[i], info, r)
+		}
+	}
+	return r.Err
+}
+
+// commandGroups holds the commands that have sub-commands.
+var commandGroups = map[string]bool{
+	"migrate":     true,
+	"schema":      true,
+	"schema plan": true,
+}
+
+// commandName returns the name of the (sub)command invoked by the given arguments.
+func commandName(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	name := args[0]
+	for _, a := range args[1:] {
+		if !commandGroups[name] || strings.HasPrefix(a, "-") {
+			break
+		}
+		name += " " + a
+	}
+	return name
+}
+
+// exitCode returns the exit code reported by the given error.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,380 @@
+// Package install downloads, verifies and caches atlas-cli binaries,
+// and creates atlasexec clients that are pinned to a specific version.
+//
+//	c, err := install.NewClient(ctx, "", "v0.32.0")
+//	if err != nil {
+//		log.Fatalf("failed to install atlas: %v", err)
+//	}
+package install
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+)
+
+// Well-known versions that are resolved to the most recent build
+// of their release channel, and therefore refreshed once in a while.
+const (
+	VersionLatest = "latest"
+	VersionCanary = "canary"
+)
+
+// DefaultBaseURL is the default location of the atlas-cli releases.
+const DefaultBaseURL = "https://release.ariga.io/atlas"
+
+type (
+	// Installer resolves atlas-cli versions to binaries stored in a local cache,
+	// downloading and verifying them on first use. The zero value is ready to use.
+	Installer struct {
+		// BaseURL is the location of the releases, e.g. an internal mirror.
+		// The binaries are expected at <BaseURL>/atlas-<os>-<arch>-<version>,
+		// and their SHA256 checksums at the same location with the ".sha256" suffix.
+		// Defaults to DefaultBaseURL.
+		BaseURL string
+		// CacheDir is the directory the binaries are stored at.
+		// Defaults to the "atlasexec" directory under os.UserCacheDir.
+		CacheDir string
+		// HTTPClient is used to download the binaries. Defaults to http.DefaultClient.
+		HTTPClient *http.Client
+		// OS and Arch of the binaries. Default to runtime.GOOS and runtime.GOARCH.
+		OS, Arch string
+		// TTL controls how long binaries of the "latest" and "canary"
+		// versions are reused before they are downloaded again.
+		// Defaults to 24 hours.
+		TTL time.Duration
+		// LockTimeout limits the time spent waiting for concurrent
+		// installs of the same version. Defaults to 5 minutes.
+		LockTimeout time.Duration
+	}
+	// ChecksumError is returned when the checksum of a downloaded binary
+	// does not match the published one.
+	ChecksumError struct {
+		URL              string
+		Expected, Actual string
+	}
+)
+
+// NewClient installs the given version using a zero Installer,
+// and returns an atlasexec.Client that runs it.
+func NewClient(ctx context.Context, workingDir, version string, opts ...atlasexec.ClientOption) (*atlasexec.Client, error) {
+	return (&Installer{}).NewClient(ctx, workingDir, version, opts...)
+}
+
+// NewClient installs the given version and returns an atlasexec.Client that runs it.
+func (i *Installer) NewClient(ctx context.Context, workingDir, version string, opts ...atlasexec.ClientOption) (*atlasexec.Client, error) {
+	path, err := i.Install(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	return atlasexec.NewClient(workingDir, path, opts...)
+}
+
+// Install returns the path to the atlas-cli binary of the given version, downloading
+// it if it does not exist in the cache. The version is either an exact release, such
+// as "v0.32.0", or one of VersionLatest and VersionCanary. An empty version is treated
+// as VersionLatest. If a refresh of VersionLatest or VersionCanary fails, the cached
+// binary is used until the next refresh.
+func (i *Installer) Install(ctx context.Context, version string) (string, error) {
+	version, err := normalize(version)
+	if err != nil {
+		return "", err
+	}
+	dir, err := i.cacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("install: creating cache directory: %w", err)
+	}
+	path := filepath.Join(dir, i.binaryName())
+	if i.cached(path, version) {
+		return path, nil
+	}
+	unlock, err := i.lock(ctx, path+".lock")
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	// The binary might have been installed while waiting for the lock.
+	if i.cached(path, version) {
+		return path, nil
+	}
+	if err := i.download(ctx, version, path); err != nil {
+		// Binaries of release channels are kept until they are refreshed.
+		if _, serr := os.Stat(path); serr == nil {
+			return path, nil
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// URL returns the download URL of the given version.
+func (i *Installer) URL(version string) (string, error) {
+	version, err := normalize(version)
+	if err != nil {
+		return "", err
+	}
+	base := i.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	goos, goarch := i.OS, i.Arch
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	u := fmt.Sprintf("%s/atlas-%s-%s-%s", strings.TrimSuffix(base, "/"), goos, goarch, version)
+	if goos == "windows" {
+		u += ".exe"
+	}
+	return u, nil
+}
+
+// cached reports if the binary at the given path can be used.
+func (i *Installer) cached(path, version string) bool {
+	fi, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return false
+	case version == VersionLatest || version == VersionCanary:
+		ttl := i.TTL
+		if ttl == 0 {
+			ttl = 24 * time.Hour
+		}
+		return time.Since(fi.ModTime()) < ttl
+	default:
+		return true
+	}
+}
+
+// download downloads and verifies the binary of the given
+// version, and atomically moves it to the given path.
+func (i *Installer) download(ctx context.Context, version, path string) error {
+	u, err := i.URL(version)
+	if err != nil {
+		return err
+	}
+	sum, err := i.fetchChecksum(ctx, u+".sha256")
+	if err != nil {
+		return err
+	}
+	body, err := i.get(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	f, err := os.CreateTemp(filepath.Dir(path), "atlas-*.tmp")
+	if err != nil {
+		return fmt.Errorf("install: creating temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		f.Close()
+		return fmt.Errorf("install: downloading %s: %w", u, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, sum) {
+		return &ChecksumError{URL: u, Expected: sum, Actual: actual}
+	}
+	if err := os.Chmod(f.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// fetchChecksum returns the hex-encoded checksum published at the given URL.
+// The file is expected to be in the format of sha256sum, i.e. "<hash>  <name>".
+func (i *Installer) fetchChecksum(ctx context.Context, u string) (string, error) {
+	body, err := i.get(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	buf, err := io.ReadAll(io.LimitReader(body, 1<<10))
+	if err != nil {
+		return "", fmt.Errorf("install: reading checksum %s: %w", u, err)
+	}
+	fields := strings.Fields(string(buf))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("install: empty checksum file %s", u)
+	}
+	return fields[0], nil
+}
+
+func (i *Installer) get(ctx context.Context, u string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "AtlasExec/Install")
+	client := i.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("install: downloading %s: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("install: downloading %s: unexpected status code %d", u, res.StatusCode)
+	}
+	return res.Body, nil
+}
+
+// lock acquires an exclusive lock file at the given path. The lock file holds a
+// token of its owner, and its modification time is refreshed while it is held.
+// Lock files left by crashed processes are removed once they were not refreshed
+// for the lock timeout.
+func (i *Installer) lock(ctx context.Context, path string) (func(), error) {
+	timeout := i.LockTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.WriteString(token)
+			if err2 := f.Close(); err == nil {
+				err = err2
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("install: writing lock: %w", err)
+			}
+			return refreshLock(path, token, timeout/3), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("install: acquiring lock: %w", err)
+		}
+		if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) > timeout {
+			// Remove the stale lock, unless it was taken over in the meantime.
+			if owner, err := os.ReadFile(path); err == nil {
+				if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) > timeout {
+					removeLock(path, string(owner))
+				}
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("install: waiting for lock %s: %w", path, ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// refreshLock refreshes the modification time of the lock file at the given
+// interval, and returns a function that stops it and releases the lock.
+func refreshLock(path, token string, interval time.Duration) func() {
+	var (
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		t := time.NewTicker(max(interval, 10*time.Millisecond))
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-t.C:
+				os.Chtimes(path, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		removeLock(path, token)
+	}
+}
+
+// removeLock removes the lock file at the given path, if it is owned by token.
+func removeLock(path, token string) {
+	if owner, err := os.ReadFile(path); err == nil && string(owner) == token {
+		os.Remove(path)
+	}
+}
+
+// lockToken returns a random token identifying the owner of a lock.
+func lockToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("install: generating lock token: %w", err)
+	}
+	return fmt.Sprintf("%d-%s", os.Getpid(), hex.EncodeToString(b)), nil
+}
+
+func (i *Installer) cacheDir() (string, error) {
+	if i.CacheDir != "" {
+		return i.CacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("install: resolving cache directory: %w", err)
+	}
+	return filepath.Join(dir, "atlasexec"), nil
+}
+
+func (i *Installer) binaryName() string {
+	if i.OS == "windows" || i.OS == "" && runtime.GOOS == "windows" {
+		return "atlas.exe"
+	}
+	return "atlas"
+}
+
+// reVersion matches exact release versions, e.g. "v0.32.0" or "v0.32.1-6d5a8d0-canary".
+var reVersion = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// normalize returns the canonical form of the given version. It returns an
+// error for invalid versions, as versions are used in paths and URLs.
+func normalize(v string) (string, error) {
+	switch v = strings.TrimSpace(v); {
+	case v == "":
+		return VersionLatest, nil
+	case v == VersionLatest, v == VersionCanary:
+		return v, nil
+	case !strings.HasPrefix(v, "v"):
+		v = "v" + v
+	}
+	if !reVersion.MatchString(v) {
+		return "", fmt.Errorf("install: invalid version %q", v)
+	}
+	return v, nil
+}
+
+// Error implements the error interface.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("install: checksum mismatch for %s: expected %s, got %s", e.URL, e.Expected, e.Actual)
+}
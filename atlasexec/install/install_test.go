@@ -0,0 +1,195 @@
+package install_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/install"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstaller(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	var (
+		downloads atomic.Int32
+		binaries  = map[string]string{
+			"v0.32.0": "#!/bin/sh\necho 'atlas version v0.32.0'\n",
+			"latest":  "#!/bin/sh\necho 'atlas version v0.33.1'\n",
+		}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/atlas-linux-amd64-")
+		if v, ok := strings.CutSuffix(name, ".sha256"); ok {
+			sum := sha256.Sum256([]byte(binaries[v]))
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  atlas\n"))
+			return
+		}
+		if b, ok := binaries[name]; ok {
+			downloads.Add(1)
+			w.Write([]byte(b))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	i := &install.Installer{
+		BaseURL:  srv.URL,
+		CacheDir: t.TempDir(),
+		OS:       "linux",
+		Arch:     "amd64",
+		TTL:      time.Hour,
+	}
+	u, err := i.URL("0.32.0")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/atlas-linux-amd64-v0.32.0", u)
+
+	ctx := context.Background()
+	c, err := i.NewClient(ctx, "", "0.32.0")
+	require.NoError(t, err)
+	v, err := c.Version(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &atlasexec.Version{Version: "0.32.0"}, v)
+	require.EqualValues(t, 1, downloads.Load())
+
+	// Cached binaries are not downloaded again.
+	path, err := i.Install(ctx, "v0.32.0")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(i.CacheDir, "v0.32.0", "atlas"), path)
+	require.EqualValues(t, 1, downloads.Load())
+
+	// Concurrent installs download the binary once.
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 5)
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := i.Install(ctx, "")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 2, downloads.Load())
+
+	// The latest version is refreshed once the TTL has passed.
+	path, err = i.Install(ctx, install.VersionLatest)
+	require.NoError(t, err)
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, past, past))
+	_, err = i.Install(ctx, install.VersionLatest)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, downloads.Load())
+
+	// The cached binary is used if the refresh fails.
+	require.NoError(t, os.Chtimes(path, past, past))
+	delete(binaries, "latest")
+	cached, err := i.Install(ctx, install.VersionLatest)
+	require.NoError(t, err)
+	require.Equal(t, path, cached)
+	require.EqualValues(t, 3, downloads.Load())
+
+	// Unknown versions.
+	_, err = i.Install(ctx, "v0.0.1")
+	require.ErrorContains(t, err, "unexpected status code 404")
+
+	// Invalid versions are rejected before they are used in paths.
+	for _, v := range []string{"v1/../../../tmp", "../v0.32.0", "v0.32", "latest/x"} {
+		_, err = i.Install(ctx, v)
+		require.ErrorContains(t, err, "install: invalid version")
+	}
+	entries, err := os.ReadDir(i.CacheDir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.Equal(t, []string{"latest", "v0.0.1", "v0.32.0"}, names)
+}
+
+func TestInstaller_Checksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			w.Write([]byte("deadbeef"))
+			return
+		}
+		w.Write([]byte("tampered"))
+	}))
+	t.Cleanup(srv.Close)
+	i := &install.Installer{BaseURL: srv.URL, CacheDir: t.TempDir()}
+	_, err := i.Install(context.Background(), "v0.32.0")
+	var checksumErr *install.ChecksumError
+	require.ErrorAs(t, err, &checksumErr)
+	require.Equal(t, "deadbeef", checksumErr.Expected)
+	require.NoFileExists(t, filepath.Join(i.CacheDir, "v0.32.0", "atlas"))
+	entries, err := os.ReadDir(filepath.Join(i.CacheDir, "v0.32.0"))
+	require.NoError(t, err)
+	require.Empty(t, entries, "temporary and lock files should be removed")
+}
+
+func TestInstaller_Lock(t *testing.T) {
+	const bin = "#!/bin/sh\necho 'atlas version v0.32.0'\n"
+	var (
+		downloads atomic.Int32
+		started   = make(chan struct{})
+		release   = make(chan struct{})
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			sum := sha256.Sum256([]byte(bin))
+			w.Write([]byte(hex.EncodeToString(sum[:])))
+			return
+		}
+		if downloads.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		w.Write([]byte(bin))
+	}))
+	t.Cleanup(srv.Close)
+	i := &install.Installer{BaseURL: srv.URL, CacheDir: t.TempDir(), LockTimeout: 300 * time.Millisecond}
+	ctx := context.Background()
+	lock := filepath.Join(i.CacheDir, "v0.32.0", "atlas.lock")
+
+	// A download that outlives the lock timeout keeps its lock.
+	done := make(chan error)
+	go func() {
+		_, err := i.Install(ctx, "v0.32.0")
+		done <- err
+	}()
+	<-started
+	_, err := i.Install(ctx, "v0.32.0")
+	require.ErrorContains(t, err, "waiting for lock")
+	close(release)
+	require.NoError(t, <-done)
+	require.EqualValues(t, 1, downloads.Load())
+	require.NoFileExists(t, lock)
+
+	// Stale locks left by crashed processes are taken over.
+	require.NoError(t, os.Remove(filepath.Join(i.CacheDir, "v0.32.0", "atlas")))
+	require.NoError(t, os.WriteFile(lock, []byte("1-crashed"), 0644))
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lock, past, past))
+	_, err = i.Install(ctx, "v0.32.0")
+	require.NoError(t, err)
+	require.NoFileExists(t, lock)
+}
@@ -0,0 +1,78 @@
+package atlasexec
+
+import "context"
+
+//go:generate go run ./internal/genfake -out atlasexectest/client_gen.go
+
+type (
+	// Interface describes the commands of the Client. It allows services to depend
+	// on an interface rather than on *Client, to wrap it or to replace it in tests.
+	// See atlasexectest.FakeClient for a fake implementation.
+	//
+	// New methods may be added to the interface in minor releases, as new commands
+	// are supported by the Client. Implementations outside this module should embed
+	// one of its implementations to stay compatible.
+	Interface interface {
+		Migrator
+		SchemaManager
+		PlanManager
+		CloudClient
+		// Version runs the 'version' command.
+		Version(ctx context.Context, opts ...ClientOption) (*Version, error)
+		// Run runs the atlas-cli with the given arguments.
+		Run(ctx context.Context, args ...string) (string, error)
+	}
+	// Migrator describes the commands of versioned migrations.
+	Migrator interface {
+		MigrateApply(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) (*MigrateApply, error)
+		MigrateApplySlice(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) ([]*MigrateApply, error)
+		MigrateApplyStream(ctx context.Context, params *MigrateApplyParams, opts ...ClientOption) (Stream[*MigrateEvent], error)
+		MigrateCheckpoint(ctx context.Context, params *MigrateCheckpointParams, opts ...ClientOption) (*File, error)
+		MigrateDown(ctx context.Context, params *MigrateDownParams, opts ...ClientOption) (*MigrateDown, error)
+		MigrateDownStream(ctx context.Context, params *MigrateDownParams, opts ...ClientOption) (Stream[*MigrateEvent], error)
+		MigrateDiff(ctx context.Context, params *MigrateDiffParams, opts ...ClientOption) (*MigrateDiff, error)
+		MigrateHash(ctx context.Context, params *MigrateHashParams, opts ...ClientOption) error
+		MigrateImport(ctx context.Context, params *MigrateImportParams, opts ...ClientOption) ([]*File, error)
+		MigrateLint(ctx context.Context, params *MigrateLintParams, opts ...ClientOption) (*SummaryReport, error)
+		MigrateLintError(ctx context.Context, params *MigrateLintParams, opts ...ClientOption) error
+		MigrateLs(ctx context.Context, params *MigrateLsParams, opts ...ClientOption) ([]string, error)
+		MigrateNew(ctx context.Context, params *MigrateNewParams, opts ...ClientOption) (*File, error)
+		MigratePush(ctx context.Context, params *MigratePushParams, opts ...ClientOption) (string, error)
+		MigrateRebase(ctx context.Context, params *MigrateRebaseParams, opts ...ClientOption) error
+		MigrateSet(ctx context.Context, params *MigrateSetParams, opts ...ClientOption) (*MigrateSet, error)
+		MigrateStatus(ctx context.Context, params *MigrateStatusParams, opts ...ClientOption) (*MigrateStatus, error)
+		MigrateTest(ctx context.Context, params *MigrateTestParams, opts ...ClientOption) (string, error)
+		MigrateValidate(ctx context.Context, params *MigrateValidateParams, opts ...ClientOption) error
+	}
+	// SchemaManager describes the commands of declarative migrations.
+	SchemaManager interface {
+		SchemaApply(ctx context.Context, params *SchemaApplyParams, opts ...ClientOption) (*SchemaApply, error)
+		SchemaApplySlice(ctx context.Context, params *SchemaApplyParams, opts ...ClientOption) ([]*SchemaApply, error)
+		SchemaClean(ctx context.Context, params *SchemaCleanParams, opts ...ClientOption) (*SchemaClean, error)
+		SchemaDiff(ctx context.Context, params *SchemaDiffParams, opts ...ClientOption) (*SchemaDiff, error)
+		SchemaInspect(ctx context.Context, params *SchemaInspectParams, opts ...ClientOption) (string, error)
+		SchemaLint(ctx context.Context, params *SchemaLintParams, opts ...ClientOption) (*SchemaLintReport, error)
+		SchemaPush(ctx context.Context, params *SchemaPushParams, opts ...ClientOption) (*SchemaPush, error)
+		SchemaTest(ctx context.Context, params *SchemaTestParams, opts ...ClientOption) (string, error)
+	}
+	// PlanManager describes the commands of declarative migration plans.
+	PlanManager interface {
+		SchemaPlan(ctx context.Context, params *SchemaPlanParams, opts ...ClientOption) (*SchemaPlan, error)
+		SchemaPlanApprove(ctx context.Context, params *SchemaPlanApproveParams, opts ...ClientOption) (*SchemaPlanApprove, error)
+		SchemaPlanLint(ctx context.Context, params *SchemaPlanLintParams, opts ...ClientOption) (*SchemaPlan, error)
+		SchemaPlanList(ctx context.Context, params *SchemaPlanListParams, opts ...ClientOption) ([]SchemaPlanFile, error)
+		SchemaPlanPull(ctx context.Context, params *SchemaPlanPullParams, opts ...ClientOption) (string, error)
+		SchemaPlanPush(ctx context.Context, params *SchemaPlanPushParams, opts ...ClientOption) (string, error)
+		SchemaPlanValidate(ctx context.Context, params *SchemaPlanValidateParams, opts ...ClientOption) error
+	}
+	// CloudClient describes the commands that interact with Atlas Cloud.
+	CloudClient interface {
+		Login(ctx context.Context, params *LoginParams, opts ...ClientOption) error
+		Logout(ctx context.Context, opts ...ClientOption) error
+		WhoAmI(ctx context.Context, params *WhoAmIParams, opts ...ClientOption) (*WhoAmI, error)
+		Copilot(ctx context.Context, params *CopilotParams, opts ...ClientOption) (Copilot, error)
+		CopilotStream(ctx context.Context, params *CopilotParams, opts ...ClientOption) (Stream[*CopilotMessage], error)
+	}
+)
+
+var _ Interface = (*Client)(nil)
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"ariga.io/atlas-go-sdk/atlasexec"
 	"github.com/stretchr/testify/require"
@@ -44,6 +45,135 @@ func Test_SQLite(t *testing.T) {
 	})
 }
 
+func Test_SQLiteMigrateCommands(t *testing.T) {
+	runTestWithVersions(t, []string{"latest"}, "versioned-basic", func(t *testing.T, ver *atlasexec.Version, wd *atlasexec.WorkingDir, c *atlasexec.Client) {
+		const (
+			url = "sqlite://file.db?_fk=1"
+			dev = "sqlite://dev?mode=memory"
+			dir = "file://migrations"
+		)
+		ctx := context.Background()
+		files, err := c.MigrateLs(ctx, &atlasexec.MigrateLsParams{DirURL: dir})
+		require.NoError(t, err)
+		require.Equal(t, []string{"20240112070806.sql"}, files)
+		require.NoError(t, c.MigrateValidate(ctx, &atlasexec.MigrateValidateParams{DirURL: dir, DevURL: dev}))
+
+		// Create a new file with content.
+		f, err := c.MigrateNew(ctx, &atlasexec.MigrateNewParams{
+			DirURL:  dir,
+			Name:    "add_t2",
+			Content: "CREATE TABLE t2(c1 int);\n",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "add_t2", f.Description)
+		require.Equal(t, "CREATE TABLE t2(c1 int);\n", f.Content)
+		files, err = c.MigrateLs(ctx, &atlasexec.MigrateLsParams{DirURL: dir, Latest: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{f.Name}, files)
+		require.NoError(t, c.MigrateValidate(ctx, &atlasexec.MigrateValidateParams{DirURL: dir, DevURL: dev}))
+
+		// Invalid statements are reported.
+		_, err = c.MigrateNew(ctx, &atlasexec.MigrateNewParams{
+			DirURL:  dir,
+			Name:    "broken",
+			Content: "CREATE TABLE t1(c1 int);\n",
+		})
+		require.NoError(t, err)
+		err = c.MigrateValidate(ctx, &atlasexec.MigrateValidateParams{DirURL: dir, DevURL: dev})
+		var verr *atlasexec.MigrateValidateError
+		require.ErrorAs(t, err, &verr)
+		require.Len(t, verr.Failures, 1)
+		require.Equal(t, atlasexec.ValidateStatement, verr.Failures[0].Kind)
+		require.Equal(t, "CREATE TABLE t1(c1 int);", verr.Failures[0].Stmt)
+
+		// Mark the first file as applied.
+		s, err := c.MigrateSet(ctx, &atlasexec.MigrateSetParams{URL: url, DirURL: dir, Version: "20240112070806"})
+		require.NoError(t, err)
+		require.Equal(t, "20240112070806", s.Current)
+		require.Len(t, s.Added, 1)
+		status, err := c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{URL: url, DirURL: dir})
+		require.NoError(t, err)
+		require.Equal(t, "20240112070806", status.Current)
+	})
+}
+
+func Test_SQLiteMigrateImport(t *testing.T) {
+	runTestWithVersions(t, []string{"latest"}, "", func(t *testing.T, ver *atlasexec.Version, wd *atlasexec.WorkingDir, c *atlasexec.Client) {
+		ctx := context.Background()
+		files, err := c.MigrateImport(ctx, &atlasexec.MigrateImportParams{
+			FromFS: fstest.MapFS{
+				"20240112070806_init.sql": {Data: []byte("-- +goose Up\nCREATE TABLE t1(c1 int);\n\n-- +goose Down\nDROP TABLE t1;\n")},
+			},
+			FromFormat: atlasexec.DirFormatGoose,
+			ToURL:      "file://migrations",
+		})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.Equal(t, "20240112070806", files[0].Version)
+		require.Contains(t, files[0].Content, "CREATE TABLE t1(c1 int);")
+		require.NotContains(t, files[0].Content, "DROP TABLE", "down migrations are not imported")
+
+		r, err := c.MigrateApply(ctx, &atlasexec.MigrateApplyParams{
+			URL:    "sqlite://file.db",
+			DirURL: "file://migrations",
+		})
+		require.NoError(t, err)
+		require.Len(t, r.Applied, 1)
+	})
+}
+
+func Test_SQLiteMigrateCheckpoint(t *testing.T) {
+	runTestWithVersions(t, []string{"latest"}, "versioned-basic", func(t *testing.T, ver *atlasexec.Version, wd *atlasexec.WorkingDir, c *atlasexec.Client) {
+		const dir = "file://migrations"
+		ctx := context.Background()
+		f, err := c.MigrateCheckpoint(ctx, &atlasexec.MigrateCheckpointParams{
+			DevURL: "sqlite://dev?mode=memory",
+			DirURL: dir,
+			Name:   "v1",
+			Tag:    "v1.0.0",
+		})
+		require.NoError(t, err)
+		require.True(t, f.Checkpoint)
+		require.Equal(t, "v1.0.0", f.Tag)
+		require.Contains(t, f.Content, "CREATE TABLE `t1`")
+
+		// A new database starts from the checkpoint.
+		s, err := c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{URL: "sqlite://file.db", DirURL: dir})
+		require.NoError(t, err)
+		require.Equal(t, f.Version, s.LatestVersion())
+		n, _ := s.Amount("")
+		require.Equal(t, uint64(1), n)
+		require.True(t, s.Pending[len(s.Pending)-1].Checkpoint)
+	})
+}
+
+func Test_SQLiteSchemaDiff(t *testing.T) {
+	runTestWithVersions(t, []string{"latest"}, "", func(t *testing.T, ver *atlasexec.Version, wd *atlasexec.WorkingDir, c *atlasexec.Client) {
+		ctx := context.Background()
+		_, err := wd.WriteFile("v1.sql", []byte("CREATE TABLE t1(c1 int);"))
+		require.NoError(t, err)
+		_, err = wd.WriteFile("v2.sql", []byte("CREATE TABLE t1(c1 int);\nCREATE TABLE t2(c1 int);"))
+		require.NoError(t, err)
+		d, err := c.SchemaDiff(ctx, &atlasexec.SchemaDiffParams{
+			From:   []string{"file://v1.sql"},
+			To:     []string{"file://v2.sql"},
+			DevURL: "sqlite://dev?mode=memory",
+		})
+		require.NoError(t, err)
+		require.Len(t, d.Stmts, 1)
+		require.Contains(t, d.Stmts[0].Text, "CREATE TABLE `t2`")
+
+		// Identical states.
+		d, err = c.SchemaDiff(ctx, &atlasexec.SchemaDiffParams{
+			From:   []string{"file://v2.sql"},
+			To:     []string{"file://v2.sql"},
+			DevURL: "sqlite://dev?mode=memory",
+		})
+		require.NoError(t, err)
+		require.True(t, d.Empty())
+	})
+}
+
 func Test_PostgreSQL(t *testing.T) {
 	u := os.Getenv("ATLASEXEC_E2ETEST_POSTGRES_URL")
 	if u == "" {
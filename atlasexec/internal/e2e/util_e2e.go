@@ -3,14 +3,12 @@ package e2etest
 import (
 	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/install"
 )
 
 const testFixtureDir = "testdata"
@@ -32,9 +30,10 @@ func runTestWithVersions(t *testing.T, versions []string, fixtureName string, cb
 			if localBinPath := os.Getenv("ATLASEXEC_E2ETEST_ATLAS_PATH"); localBinPath != "" {
 				execPath = localBinPath
 			} else {
-				execPath = downloadAtlas(t, av)
-				if err := os.Chmod(execPath, 0755); err != nil {
-					t.Fatalf("unable to make atlas executable: %s", err)
+				var err error
+				execPath, err = (&install.Installer{}).Install(context.Background(), av)
+				if err != nil {
+					t.Fatalf("unable to install atlas %q: %s", av, err)
 				}
 			}
 			c, err := atlasexec.NewClient("", execPath)
@@ -75,47 +74,3 @@ func runTestWithVersions(t *testing.T, versions []string, fixtureName string, cb
 		})
 	}
 }
-
-func downloadAtlas(t *testing.T, version string) string {
-	t.Helper()
-	c := http.DefaultClient
-	req, err := http.NewRequest(http.MethodGet, "https://atlasgo.sh?test=1", nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	req.Header.Set("User-Agent", "AtlasExec/Integration-Test")
-	res, err := c.Do(req)
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		t.Fatalf("unexpected status code: %d", res.StatusCode)
-	}
-	path := filepath.Join(t.TempDir(), "installer.sh")
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if _, err = io.Copy(f, res.Body); err != nil {
-		f.Close()
-		t.Fatal(err)
-	} else if err = f.Close(); err != nil {
-		t.Fatal(err)
-	}
-	atlasBin := filepath.Join(t.TempDir(), "atlas")
-	cmd := exec.Command(path,
-		"--user-agent", "AtlasExec/Integration-Test",
-		"--output", atlasBin,
-		"--no-install",
-	)
-	cmd.Env = append(os.Environ(), fmt.Sprintf("ATLAS_VERSION=%s", version))
-	if testing.Verbose() {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-	if err = cmd.Run(); err != nil {
-		t.Fatal(err)
-	}
-	return atlasBin
-}
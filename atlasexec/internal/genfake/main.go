@@ -0,0 +1,201 @@
+// Command genfake generates atlasexectest.FakeClient, a fake implementation
+// of atlasexec.Interface, from the interface declarations in interface.go.
+// It is invoked using go generate from the atlasexec directory.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		src = flag.String("src", "interface.go", "file declaring the interfaces")
+		out = flag.String("out", "atlasexectest/client_gen.go", "output file")
+	)
+	flag.Parse()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, *src, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ifaces := make(map[string]*ast.InterfaceType)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if s, ok := n.(*ast.TypeSpec); ok {
+			if t, ok := s.Type.(*ast.InterfaceType); ok {
+				ifaces[s.Name.Name] = t
+			}
+		}
+		return true
+	})
+	g := &generator{fset: fset, ifaces: ifaces}
+	methods, err := g.methods("Interface")
+	if err != nil {
+		log.Fatal(err)
+	}
+	buf, err := g.generate(methods)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	fset   *token.FileSet
+	ifaces map[string]*ast.InterfaceType
+}
+
+// methods returns the methods of the given interface, including the embedded ones.
+func (g *generator) methods(name string) ([]*ast.Field, error) {
+	t, ok := g.ifaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %q was not found", name)
+	}
+	var methods []*ast.Field
+	for _, f := range t.Methods.List {
+		if len(f.Names) == 0 {
+			id, ok := f.Type.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("unexpected embedded type %T in %s", f.Type, name)
+			}
+			embedded, err := g.methods(id.Name)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, embedded...)
+			continue
+		}
+		methods = append(methods, f)
+	}
+	return methods, nil
+}
+
+func (g *generator) generate(methods []*ast.Field) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(`// Code generated by internal/genfake. DO NOT EDIT.
+
+package atlasexectest
+
+import (
+	"context"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+)
+
+// FakeClient is a fake implementation of atlasexec.Interface. Every method records
+// its call and invokes the function set in the field with the same name and the
+// "Func" suffix, e.g. MigrateApplyFunc. If the function is not set, the method
+// returns an error.
+type FakeClient struct {
+	fakeCalls
+`)
+	for _, m := range methods {
+		fmt.Fprintf(&b, "\t%sFunc %s\n", m.Names[0].Name, g.expr(m.Type))
+	}
+	b.WriteString("}\n\nvar _ atlasexec.Interface = (*FakeClient)(nil)\n")
+	for _, m := range methods {
+		if err := g.method(&b, m.Names[0].Name, m.Type.(*ast.FuncType)); err != nil {
+			return nil, err
+		}
+	}
+	return format.Source(b.Bytes())
+}
+
+func (g *generator) method(b *bytes.Buffer, name string, fn *ast.FuncType) error {
+	var (
+		params, args, record []string
+		results              []string
+	)
+	for i, p := range fn.Params.List {
+		n := fmt.Sprintf("a%d", i)
+		if len(p.Names) > 0 {
+			n = p.Names[0].Name
+		}
+		typ := g.expr(p.Type)
+		params = append(params, n+" "+typ)
+		record = append(record, n)
+		if strings.HasPrefix(typ, "...") {
+			n += "..."
+		}
+		args = append(args, n)
+	}
+	if fn.Results != nil {
+		for i, r := range fn.Results.List {
+			results = append(results, fmt.Sprintf("r%d %s", i, g.expr(r.Type)))
+		}
+	}
+	if len(results) == 0 || !strings.HasSuffix(results[len(results)-1], " error") {
+		return fmt.Errorf("method %s must return an error", name)
+	}
+	fmt.Fprintf(b, "\n// %s implements atlasexec.Interface.\n", name)
+	fmt.Fprintf(b, "func (f *FakeClient) %s(%s) (%s) {\n", name, strings.Join(params, ", "), strings.Join(results, ", "))
+	fmt.Fprintf(b, "\tf.record(%q, %s)\n", name, strings.Join(record, ", "))
+	fmt.Fprintf(b, "\tif f.%sFunc == nil {\n\t\tr%d = notImplemented(%q)\n\t\treturn\n\t}\n", name, len(results)-1, name)
+	fmt.Fprintf(b, "\treturn f.%sFunc(%s)\n}\n", name, strings.Join(args, ", "))
+	return nil
+}
+
+// expr returns the source of the given type expression, with the
+// exported identifiers of the atlasexec package qualified.
+func (g *generator) expr(e ast.Expr) string {
+	e = qualify(e)
+	var b bytes.Buffer
+	if err := format.Node(&b, g.fset, e); err != nil {
+		log.Fatal(err)
+	}
+	return b.String()
+}
+
+// qualify returns a copy of the type expression with the
+// package-local identifiers qualified with "atlasexec".
+func qualify(e ast.Expr) ast.Expr {
+	switch e := e.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("atlasexec"), Sel: ast.NewIdent(e.Name)}
+		}
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualify(e.X), Index: qualify(e.Index)}
+	case *ast.IndexListExpr:
+		idx := make([]ast.Expr, len(e.Indices))
+		for i := range e.Indices {
+			idx[i] = qualify(e.Indices[i])
+		}
+		return &ast.IndexListExpr{X: qualify(e.X), Indices: idx}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params), Results: qualifyFields(e.Results)}
+	default:
+		// Qualified identifiers, e.g. context.Context.
+		return e
+	}
+}
+
+func qualifyFields(l *ast.FieldList) *ast.FieldList {
+	if l == nil {
+		return nil
+	}
+	c := &ast.FieldList{}
+	for _, f := range l.List {
+		c.List = append(c.List, &ast.Field{Names: f.Names, Type: qualify(f.Type)})
+	}
+	return c
+}
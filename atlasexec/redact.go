@@ -0,0 +1,221 @@
+package atlasexec
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redacted is the placeholder used for masked values.
+const redacted = "xxxxx"
+
+// minSecretLen is the minimal length of secret values that are masked
+// wherever they appear in the output of commands. Shorter values are
+// masked only as whole words, to not mask parts of unrelated text.
+const minSecretLen = 4
+
+var (
+	// secretFlags holds the flags whose values are always masked.
+	secretFlags = map[string]bool{
+		"--token": true,
+	}
+	// secretEnvs holds the environment variables whose values are always masked.
+	secretEnvs = map[string]bool{
+		"ATLAS_TOKEN":           true,
+		"PGPASSWORD":            true,
+		"MYSQL_PWD":             true,
+		"AWS_SECRET_ACCESS_KEY": true,
+		"AWS_SESSION_TOKEN":     true,
+		"GITHUB_TOKEN":          true,
+		"GITLAB_TOKEN":          true,
+		EnvURL:                  true,
+		EnvDevURL:               true,
+		EnvCloudToken:           true,
+	}
+)
+
+// WithSecretVars marks the input variables with the given names as secrets. Their
+// values are masked in the messages of the errors returned by the Client, in the
+// CommandInfo passed to hooks, in stderr events and in command previews. Database
+// URL passwords, the --token flag and the values of well-known secret environment
+// variables, like ATLAS_TOKEN or PGPASSWORD, are always masked.
+//
+// Values shorter than 4 characters are masked in the output of commands only where
+// they appear as whole words. For example, the value "abc" is masked in "user abc"
+// but not in "abcdef".
+//
+//	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithSecretVars("db_password"))
+func WithSecretVars(names ...string) ClientOption {
+	return func(c *Client) {
+		vars := maps.Clone(c.secretVars)
+		if vars == nil {
+			vars = make(map[string]bool, len(names))
+		}
+		for _, n := range names {
+			vars[n] = true
+		}
+		c.secretVars = vars
+	}
+}
+
+// redactor masks secrets in the arguments and the output of a command.
+// A nil redactor masks only URL passwords and the values of secret flags.
+type redactor struct {
+	vars   map[string]bool // Names of the secret input variables.
+	values []string        // Secret values, masked wherever they appear.
+	words  []string        // Short secret values, masked only as whole words.
+}
+
+// redactor returns the redactor for the invocation with the given arguments and environment.
+func (c *Client) redactor(args, env []string) *redactor {
+	r := &redactor{vars: c.secretVars}
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && secretEnvs[k] {
+			r.addValue(v)
+		}
+	}
+	for i, a := range args {
+		var prev string
+		if i > 0 {
+			prev = args[i-1]
+		}
+		if _, v := r.arg(prev, a); v != "" {
+			r.addValue(v)
+		}
+	}
+	return r
+}
+
+// addValue adds the given value to the list of the masked values.
+func (r *redactor) addValue(v string) {
+	switch {
+	case v == "" || slices.Contains(r.values, v) || slices.Contains(r.words, v):
+		return
+	case len(v) < minSecretLen:
+		r.words = append(r.words, v)
+		return
+	}
+	r.values = append(r.values, v)
+	// Longer values are masked first, in case they contain shorter ones.
+	slices.SortFunc(r.values, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+}
+
+// args returns a copy of the given arguments with secrets masked.
+func (r *redactor) args(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		var prev string
+		if i > 0 {
+			prev = args[i-1]
+		}
+		out[i], _ = r.arg(prev, a)
+		out[i] = r.text(out[i])
+	}
+	return out
+}
+
+// arg masks the given argument, preceded by prev, and returns
+// the masked argument and the secret value it holds, if any.
+func (r *redactor) arg(prev, a string) (string, string) {
+	switch f, v, ok := strings.Cut(a, "="); {
+	case secretFlags[prev]:
+		return redacted, a
+	case ok && secretFlags[f]:
+		// Flags in the form of --flag=value.
+		return f + "=" + redacted, v
+	case prev == "--var" && r.secretVar(f):
+		return f + "=" + redacted, v
+	case ok && f == "--var":
+		// Variables in the form of --var=name=value.
+		if n, v, ok := strings.Cut(v, "="); ok && r.secretVar(n) {
+			return f + "=" + n + "=" + redacted, v
+		}
+	}
+	return redactURLs(a), ""
+}
+
+// secretVar reports if the input variable with the given name is secret.
+func (r *redactor) secretVar(name string) bool {
+	return r != nil && r.vars[name]
+}
+
+// text masks the secrets found in the given text.
+func (r *redactor) text(s string) string {
+	s = redactURLs(s)
+	if r == nil {
+		return s
+	}
+	for _, v := range r.values {
+		s = strings.ReplaceAll(s, v, redacted)
+	}
+	for _, v := range r.words {
+		s = replaceWord(s, v)
+	}
+	return s
+}
+
+// replaceWord masks the occurrences of v in s that are not
+// part of a longer word, e.g. "abc" in "abc,def" but not in "abcdef".
+func replaceWord(s, v string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, v)
+		if i == -1 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(v)
+		if (i > 0 && isWordByte(s[i-1])) || (end < len(s) && isWordByte(s[end])) {
+			b.WriteString(s[:i+1])
+			s = s[i+1:]
+			continue
+		}
+		b.WriteString(s[:i])
+		b.WriteString(redacted)
+		s = s[end:]
+	}
+}
+
+// isWordByte reports if b can be part of a word. Non-ASCII
+// bytes are considered part of a word, as they may be letters.
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// redactErr masks the secrets in the given message of err, using
+// the redactor of the CLI error wrapped by err, if exists.
+func redactErr(err error, msg string) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.redact.text(msg)
+	}
+	return redactURLs(msg)
+}
+
+// reURL matches URLs in text. Matches stop at commas, as URL lists
+// are passed to the atlas-cli as comma-separated values.
+var reURL = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>,]+`)
+
+// redactURLs masks the passwords of all URLs found in s.
+func redactURLs(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	return reURL.ReplaceAllStringFunc(s, func(m string) string {
+		u, err := url.Parse(m)
+		if err != nil || u.User == nil {
+			return m
+		}
+		if _, ok := u.User.Password(); !ok {
+			return m
+		}
+		u.User = url.UserPassword(u.User.Username(), redacted)
+		return u.String()
+	})
+}
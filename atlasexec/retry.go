@@ -0,0 +1,169 @@
+package atlasexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+type (
+	// RetryPolicy controls how commands that failed with a transient error are retried.
+	// Only commands that are safe to re-run are retried, such as 'migrate status',
+	// 'migrate lint' or 'schema inspect'. 'migrate apply' is retried only if the
+	// AllowMigrateApply is set, and only after it failed to acquire the database lock.
+	RetryPolicy struct {
+		// MaxAttempts is the maximum number of times a command is executed,
+		// including the first attempt. Defaults to 3.
+		MaxAttempts int
+		// Backoff is the delay before the first retry. It is doubled on
+		// every subsequent retry, up to MaxBackoff. Defaults to 500ms.
+		Backoff time.Duration
+		// MaxBackoff caps the delay between attempts. Defaults to 10s.
+		MaxBackoff time.Duration
+		// Jitter is the fraction, between 0 and 1, of the delay that is randomized
+		// to avoid concurrent clients from retrying at the same time. Defaults to
+		// 0.2. Set a negative value to disable it.
+		Jitter float64
+		// Retryable reports whether the given error is transient. Defaults to
+		// errors matching ErrLockTimeout or ErrConnectionRefused.
+		Retryable func(error) bool
+		// AllowMigrateApply allows retrying 'migrate apply' after an ErrLockTimeout
+		// error. In this case, no migration was executed, and the command can be
+		// safely re-run once the lock is released.
+		AllowMigrateApply bool
+	}
+	// RetryError is returned when a command failed on all of its attempts,
+	// or when the context was done while waiting for the next attempt.
+	// Use errors.As to get the error of the last attempt, e.g. Error or
+	// MigrateApplyError.
+	RetryError struct {
+		Command  string  // Name of the command, e.g. "migrate status".
+		Attempts []error // Errors of all attempts, in order.
+	}
+)
+
+// idempotentCmds holds the commands that can be safely re-run.
+var idempotentCmds = map[string]bool{
+	"migrate status":   true,
+	"migrate lint":     true,
+	"migrate test":     true,
+	"schema inspect":   true,
+	"schema lint":      true,
+	"schema test":      true,
+	"schema plan list": true,
+	"schema plan pull": true,
+	"version":          true,
+	"whoami":           true,
+}
+
+// WithRetry configures the Client to retry commands that failed with a transient error.
+func WithRetry(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// retryable reports whether the command invoked by the given arguments can be re-run after the given error.
+func (p *RetryPolicy) retryable(args []string, err error) bool {
+	switch name := commandName(args); {
+	case idempotentCmds[name]:
+	case name == "migrate apply":
+		if !p.AllowMigrateApply || !errors.Is(err, ErrLockTimeout) {
+			return false
+		}
+	default:
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return errors.Is(err, ErrLockTimeout) || errors.Is(err, ErrConnectionRefused)
+}
+
+// delay returns the time to wait before the given retry, starting at 1.
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	d, maxD := p.Backoff, p.MaxBackoff
+	if d <= 0 {
+		d = 500 * time.Millisecond
+	}
+	if maxD <= 0 {
+		maxD = 10 * time.Second
+	}
+	for i := 1; i < retry && d < maxD; i++ {
+		d *= 2
+	}
+	d = min(d, maxD)
+	j := p.Jitter
+	if j == 0 {
+		j = 0.2
+	}
+	if j = min(j, 1); j > 0 {
+		d -= time.Duration(j * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// withRetry runs fn according to the retry policy of the client.
+func (c *Client) withRetry(ctx context.Context, args []string, fn func() error) error {
+	err := fn()
+	p := c.retry
+	if err == nil || p == nil || !p.retryable(args, err) {
+		return err
+	}
+	attempts, maxAttempts := []error{err}, p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	for len(attempts) < maxAttempts {
+		t := time.NewTimer(p.delay(len(attempts)))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return &RetryError{Command: commandName(args), Attempts: append(attempts, ctx.Err())}
+		case <-t.C:
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		attempts = append(attempts, err)
+		if !p.retryable(args, err) {
+			break
+		}
+	}
+	return &RetryError{Command: commandName(args), Attempts: attempts}
+}
+
+// Error implements the error interface.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("atlasexec: command %q failed after %d attempt%s: %v", e.Command, len(e.Attempts), plural(len(e.Attempts)), e.last())
+}
+
+// Unwrap returns the errors of all attempts.
+func (e *RetryError) Unwrap() []error {
+	return e.Attempts
+}
+
+// As finds the first error in the chain of the last attempt that matches target.
+func (e *RetryError) As(target any) bool {
+	return errors.As(e.last(), target)
+}
+
+func (e *RetryError) last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// replaceLast returns err with its last attempt replaced by the given error, if err is a RetryError.
+// Otherwise, the given error is returned.
+func replaceLast(err, last error) error {
+	var rerr *RetryError
+	if !errors.As(err, &rerr) || len(rerr.Attempts) == 0 {
+		return last
+	}
+	attempts := append(rerr.Attempts[:len(rerr.Attempts)-1:len(rerr.Attempts)-1], last)
+	return &RetryError{Command: rerr.Command, Attempts: attempts}
+}
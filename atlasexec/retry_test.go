@@ -0,0 +1,113 @@
+package atlasexec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+const lockErr = "Error: acquiring database lock: sql/schema: lock is held by other session"
+
+func TestRetry(t *testing.T) {
+	policy := atlasexec.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	t.Run("Recovered", func(t *testing.T) {
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(policy))
+		failed := ex.On("migrate", "status", "--format", "{{ json . }}").
+			Stderr("Error: dial tcp 127.0.0.1:5432: connect: connection refused").
+			Times(2)
+		ok := ex.On("migrate", "status", "--format", "{{ json . }}").Stdout(`{"Current":"1"}`)
+		s, err := c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{})
+		require.NoError(t, err)
+		require.Equal(t, "1", s.Current)
+		require.Equal(t, 2, failed.Count())
+		require.Equal(t, 1, ok.Count())
+	})
+	t.Run("Exhausted", func(t *testing.T) {
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(policy))
+		ex.On("schema", "inspect", "--url", "mysql://localhost").Stderr(lockErr)
+		_, err := c.SchemaInspect(context.Background(), &atlasexec.SchemaInspectParams{URL: "mysql://localhost"})
+		var rerr *atlasexec.RetryError
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, "schema inspect", rerr.Command)
+		require.Len(t, rerr.Attempts, 3)
+		require.ErrorIs(t, err, atlasexec.ErrLockTimeout)
+		var cliErr *atlasexec.Error
+		require.ErrorAs(t, err, &cliErr)
+		require.Equal(t, lockErr, cliErr.Stderr)
+		require.Len(t, ex.Invocations(), 3)
+	})
+	t.Run("NotRetryable", func(t *testing.T) {
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(policy))
+		ex.On("migrate", "status", "--format", "{{ json . }}").Stderr("Error: checksum mismatch")
+		_, err := c.MigrateStatus(context.Background(), &atlasexec.MigrateStatusParams{})
+		var rerr *atlasexec.RetryError
+		require.False(t, errors.As(err, &rerr))
+		require.ErrorIs(t, err, atlasexec.ErrChecksumMismatch)
+		require.Len(t, ex.Invocations(), 1)
+	})
+	t.Run("Classifier", func(t *testing.T) {
+		p := policy
+		p.Retryable = func(err error) bool { return errors.Is(err, atlasexec.ErrChecksumMismatch) }
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(p))
+		ex.On("migrate", "lint", "--format", "{{ json . }}").Stderr("Error: checksum mismatch")
+		_, err := c.MigrateLint(context.Background(), &atlasexec.MigrateLintParams{})
+		require.ErrorIs(t, err, atlasexec.ErrChecksumMismatch)
+		require.Len(t, ex.Invocations(), 3)
+	})
+	t.Run("MigrateApply", func(t *testing.T) {
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(policy))
+		ex.On("migrate", "apply", "--format", "{{ json . }}").
+			Stdout(`{"Error":"acquiring database lock: sql/schema: lock is held by other session"}`).
+			ExitCode(1)
+		_, err := c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{})
+		require.ErrorIs(t, err, atlasexec.ErrLockTimeout)
+		require.Len(t, ex.Invocations(), 1, "migrate apply is not retried by default")
+
+		c, ex = atlasexectest.NewClient(t, atlasexec.WithRetry(atlasexec.RetryPolicy{
+			MaxAttempts:       2,
+			Backoff:           time.Millisecond,
+			AllowMigrateApply: true,
+		}))
+		ex.On("migrate", "apply", "--format", "{{ json . }}").
+			Stdout(`{"Error":"acquiring database lock: sql/schema: lock is held by other session"}`).
+			ExitCode(1)
+		_, err = c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{})
+		var (
+			rerr *atlasexec.RetryError
+			merr *atlasexec.MigrateApplyError
+		)
+		require.ErrorAs(t, err, &rerr)
+		require.Len(t, rerr.Attempts, 2)
+		require.ErrorAs(t, err, &merr)
+		require.ErrorIs(t, err, atlasexec.ErrLockTimeout)
+		require.Len(t, ex.Invocations(), 2)
+
+		// Other failures of migrate apply are never retried.
+		c, ex = atlasexectest.NewClient(t, atlasexec.WithRetry(atlasexec.RetryPolicy{
+			Backoff:           time.Millisecond,
+			AllowMigrateApply: true,
+		}))
+		ex.On("migrate", "apply", "--format", "{{ json . }}").Stderr("Error: dial tcp: connect: connection refused")
+		_, err = c.MigrateApply(context.Background(), &atlasexec.MigrateApplyParams{})
+		require.ErrorIs(t, err, atlasexec.ErrConnectionRefused)
+		require.Len(t, ex.Invocations(), 1)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		c, ex := atlasexectest.NewClient(t, atlasexec.WithRetry(atlasexec.RetryPolicy{Backoff: time.Hour}))
+		ex.On("migrate", "status", "--format", "{{ json . }}").Stderr(lockErr)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := c.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{})
+		var rerr *atlasexec.RetryError
+		require.ErrorAs(t, err, &rerr)
+		require.Len(t, rerr.Attempts, 2)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, err, atlasexec.ErrLockTimeout)
+		require.Len(t, ex.Invocations(), 1)
+	})
+}
@@ -0,0 +1,42 @@
+package atlasexec
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultFormat is the Go template used by Query when no format is given.
+// It prints the result of the command as JSON.
+const DefaultFormat = "{{ json . }}"
+
+// Run runs the atlas-cli with the given arguments and returns its stdout. It is an
+// escape hatch for commands and flags that are not supported by the Client yet. The
+// command is executed like the other commands of the Client, applying its environment,
+// working directory, timeout and hooks. A failure is reported using an *Error.
+//
+//	out, err := c.Run(ctx, "schema", "fmt", "schema.hcl")
+func (c *Client) Run(ctx context.Context, args ...string) (string, error) {
+	c = c.With()
+	return stringVal(c.runCommand(ctx, args))
+}
+
+// Query runs the atlas-cli with the given arguments and the given --format template,
+// and decodes its JSON output into T. If format is empty, DefaultFormat is used.
+// Like Run, it allows using commands that are not supported by the Client yet.
+//
+//	type report struct{ Current, Next string }
+//	r, err := atlasexec.Query[report](ctx, c, []string{"migrate", "status", "--env", "prod"}, "")
+func Query[T any](ctx context.Context, c *Client, args []string, format string) (*T, error) {
+	return firstResult(QuerySlice[T](ctx, c, args, format))
+}
+
+// QuerySlice is like Query, but returns all the JSON values printed by the command,
+// for example, when it runs on multiple targets.
+func QuerySlice[T any](ctx context.Context, c *Client, args []string, format string) ([]*T, error) {
+	if strings.TrimSpace(format) == "" {
+		format = DefaultFormat
+	}
+	c = c.With()
+	args = append(args[:len(args):len(args)], "--format", format)
+	return jsonDecode[T](c.runCommand(ctx, args))
+}
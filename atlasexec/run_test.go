@@ -0,0 +1,78 @@
+package atlasexec_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Run(t *testing.T) {
+	var infos []*atlasexec.CommandInfo
+	c, ex := atlasexectest.NewClient(t,
+		atlasexec.WithEnv(atlasexec.Environ{"FOO": "bar"}),
+		atlasexec.WithHooks(atlasexec.Hook{
+			Before: func(ctx context.Context, info *atlasexec.CommandInfo) context.Context {
+				infos = append(infos, info)
+				return ctx
+			},
+		}),
+	)
+	ex.On("schema", "fmt", "schema.hcl").Stdout("schema.hcl\n")
+	c = c.With(atlasexec.WithWorkingDir("project"))
+	out, err := c.Run(context.Background(), "schema", "fmt", "schema.hcl")
+	require.NoError(t, err)
+	require.Equal(t, "schema.hcl\n", out)
+	inv := ex.Invocations()[0]
+	require.Equal(t, "project", inv.Dir)
+	require.Contains(t, inv.Env, "FOO=bar")
+	require.Len(t, infos, 1)
+	require.Equal(t, "schema fmt", infos[0].Name)
+
+	ex.On("schema", "fmt", "invalid.hcl").Stderr("Error: invalid.hcl:1,1-2: Argument or block definition required")
+	_, err = c.Run(context.Background(), "schema", "fmt", "invalid.hcl")
+	var cliErr *atlasexec.Error
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, 1, cliErr.ExitCode())
+	require.ErrorIs(t, err, atlasexec.ErrHCLSyntax)
+}
+
+func TestQuery(t *testing.T) {
+	type status struct {
+		Current, Next string
+		Pending       int
+	}
+	var cliErr *atlasexec.Error
+	c, ex := atlasexectest.NewClient(t)
+	ex.On("migrate", "status", "--env", "prod", "--format", "{{ json . }}").
+		Stdout(`{"Current":"1","Next":"2","Pending":1}`)
+	s, err := atlasexec.Query[status](context.Background(), c, []string{"migrate", "status", "--env", "prod"}, "")
+	require.NoError(t, err)
+	require.Equal(t, &status{Current: "1", Next: "2", Pending: 1}, s)
+
+	// Custom templates.
+	ex.On("migrate", "status", "--env", "prod", "--format", `{{ json .Pending }}`).Stdout(`[{"Name":"2.sql"}]`)
+	files, err := atlasexec.Query[[]atlasexec.File](context.Background(), c, []string{"migrate", "status", "--env", "prod"}, `{{ json .Pending }}`)
+	require.NoError(t, err)
+	require.Len(t, *files, 1)
+	require.Equal(t, "2.sql", (*files)[0].Name)
+
+	// Multiple results.
+	ex.On("migrate", "apply", "--env", "multi", "--format", "{{ json . }}").Stdout(`{"Target":"1"}` + "\n" + `{"Target":"2"}`)
+	args := []string{"migrate", "apply", "--env", "multi"}
+	_, err = atlasexec.Query[atlasexec.MigrateApply](context.Background(), c, args, "")
+	require.Error(t, err)
+	rs, err := atlasexec.QuerySlice[atlasexec.MigrateApply](context.Background(), c, args, "")
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	require.Equal(t, "2", rs[1].Target)
+	require.Equal(t, []string{"migrate", "apply", "--env", "multi"}, args, "arguments are not modified")
+
+	// Output that is not JSON.
+	ex.On("migrate", "hash", "--format", "{{ json . }}").Stdout("ok")
+	_, err = atlasexec.Query[status](context.Background(), c, []string{"migrate", "hash"}, "")
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, "ok", cliErr.Stdout)
+}
@@ -0,0 +1,246 @@
+package atlasexec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Environment variables used to pass secrets to the atlas-cli. Project files
+// passed using ConfigURL can read them using the getenv function. For example:
+//
+//	env "prod" {
+//	  url = getenv("ATLASEXEC_URL")
+//	}
+const (
+	EnvURL        = "ATLASEXEC_URL"         // Holds the value of the --url flag.
+	EnvDevURL     = "ATLASEXEC_DEV_URL"     // Holds the value of the --dev-url flag.
+	EnvTo         = "ATLASEXEC_TO"          // Holds the value of the --to flag of 'schema apply' and 'migrate diff'.
+	EnvCloudToken = "ATLASEXEC_CLOUD_TOKEN" // Holds the Atlas Cloud token.
+)
+
+// ErrSecretArg is returned by Login when the Client is configured to keep
+// secrets out of the process arguments. Use WithCloudToken instead.
+var ErrSecretArg = errors.New("atlasexec: login passes the token as an argument, use WithCloudToken instead")
+
+// WithSecretsFromEnv configures the Client to keep credentials out of the arguments of
+// the atlas-cli process, as they are visible to other users of the machine, e.g. using
+// ps. Database URLs that contain a password are moved to environment variables, and the
+// Client generates a project file that reads them using getenv:
+//
+//   - The --url and --dev-url flags are moved to EnvURL and EnvDevURL.
+//   - The --to flag of 'schema apply' and 'migrate diff' is moved to EnvTo.
+//
+// Commands return an error, instead of exposing the password, in the following cases:
+//
+//   - The command uses a project file, that is, ConfigURL or Env are set, as the Client
+//     cannot tell if the project file reads the variables. Set the URLs in the project
+//     file instead, for example, using getenv with variables set by WithEnv.
+//   - The password is passed to a flag that has no matching project file attribute,
+//     like the --from flag of 'schema diff' and 'schema plan'.
+//   - The password is part of a URL list, e.g. SchemaApplyParams.To holding multiple
+//     comma-separated URLs, as the project file attributes hold a single URL.
+//
+// Input variables (Vars) are still passed as arguments. Mark them with WithSecretVars
+// to mask them in errors and hooks, or read them in the project file using getenv.
+// Login returns ErrSecretArg when this option is set, use WithCloudToken instead.
+func WithSecretsFromEnv() ClientOption {
+	return func(c *Client) {
+		c.secretsFromEnv = true
+	}
+}
+
+// WithCloudToken configures the Atlas Cloud token used by the commands, instead of the
+// credentials stored by Login. The token is passed to the atlas-cli in the EnvCloudToken
+// environment variable. If the command does not use a project file, the Client generates
+// one that reads it. Otherwise, the project file is expected to read it. For example:
+//
+//	atlas {
+//	  cloud {
+//	    token = getenv("ATLASEXEC_CLOUD_TOKEN")
+//	  }
+//	}
+func WithCloudToken(token string) ClientOption {
+	return func(c *Client) {
+		c.cloudToken = token
+	}
+}
+
+var (
+	// configCommands holds the commands that accept a project file.
+	configCommands = map[string]bool{
+		"migrate": true,
+		"schema":  true,
+		"whoami":  true,
+	}
+	// urlFlags holds the flags that accept database URLs, and the environment
+	// variables of the ones that can be read from the project file by all commands.
+	urlFlags = map[string]string{
+		"--url":     EnvURL,
+		"--dev-url": EnvDevURL,
+		"--from":    "",
+		"--to":      "",
+	}
+	// srcCommands holds the commands that read the --to flag
+	// from the src attribute of the project file.
+	srcCommands = map[string]bool{
+		"schema apply": true,
+		"migrate diff": true,
+	}
+)
+
+// secretArgs moves the secrets found in the given arguments to environment variables.
+// It returns the new arguments and the variables to add to the environment of the command.
+func (c *Client) secretArgs(args []string) ([]string, Environ, error) {
+	if c.secretsFromEnv && len(args) > 0 && args[0] == "login" {
+		return nil, nil, ErrSecretArg
+	}
+	if (!c.secretsFromEnv && c.cloudToken == "") || len(args) == 0 || !configCommands[args[0]] {
+		return args, nil, nil
+	}
+	var (
+		env    = make(Environ)
+		out    = make([]string, 0, len(args))
+		moved  []string // Flags moved to the environment.
+		custom bool     // Command uses a custom project file.
+	)
+	for i := 0; i < len(args); i++ {
+		f, v, ok := strings.Cut(args[i], "=")
+		if !ok && i+1 < len(args) {
+			v = args[i+1]
+		}
+		name, isURL := urlFlags[f]
+		switch {
+		case f == "--config" || f == "--env":
+			custom = true
+		case c.secretsFromEnv && isURL && hasPassword(v):
+			if f == "--to" && len(args) > 1 && srcCommands[args[0]+" "+args[1]] {
+				name = EnvTo
+			}
+			// Lists are passed as comma-separated values.
+			if name == "" || (name == EnvTo && strings.Contains(v, ",")) {
+				return nil, nil, fmt.Errorf("atlasexec: cannot pass the password of flag %s through the environment", f)
+			}
+			if _, exists := env[name]; exists {
+				return nil, nil, fmt.Errorf("atlasexec: cannot pass multiple %s flags through the environment", f)
+			}
+			env[name] = v
+			moved = append(moved, f)
+			if !ok {
+				i++
+			}
+			continue
+		}
+		out = append(out, args[i])
+	}
+	if custom && len(moved) > 0 {
+		return nil, nil, fmt.Errorf("atlasexec: cannot pass the password of flag %s through the environment of a custom project file, set it in the project file instead", moved[0])
+	}
+	if c.cloudToken != "" {
+		env[EnvCloudToken] = c.cloudToken
+	}
+	if len(env) == 0 || custom {
+		return out, env, nil
+	}
+	path, err := genConfig(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	out = append(out, "--config", "file://"+filepath.ToSlash(path))
+	if len(moved) > 0 {
+		out = append(out, "--env", "atlasexec")
+	}
+	return out, env, nil
+}
+
+// hasPassword reports if the given value is a URL with a password, or
+// a comma-separated list of URLs where at least one has a password.
+func hasPassword(s string) bool {
+	for _, e := range strings.Split(s, ",") {
+		u, err := url.Parse(e)
+		if err != nil || u.User == nil {
+			continue
+		}
+		if _, ok := u.User.Password(); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// genConfigs holds the project files generated by the process. They are written
+// to a private directory, and cached by their content.
+var genConfigs struct {
+	sync.Mutex
+	dir   string
+	paths map[string]string
+}
+
+// genConfig returns the path to a project file that reads the given environment
+// variables. The file holds no secrets, and is shared by all commands using it.
+func genConfig(env Environ) (string, error) {
+	var b strings.Builder
+	b.WriteString("# Generated by atlasexec.\n")
+	if _, ok := env[EnvCloudToken]; ok {
+		fmt.Fprintf(&b, "atlas {\n  cloud {\n    token = getenv(%q)\n  }\n}\n", EnvCloudToken)
+	}
+	var attrs []string
+	for _, a := range []struct{ attr, name string }{{"url", EnvURL}, {"dev", EnvDevURL}, {"src", EnvTo}} {
+		if _, ok := env[a.name]; ok {
+			attrs = append(attrs, fmt.Sprintf("  %-4s = getenv(%q)\n", a.attr, a.name))
+		}
+	}
+	if len(attrs) > 0 {
+		b.WriteString("env {\n  name = atlas.env\n")
+		b.WriteString(strings.Join(attrs, ""))
+		b.WriteString("}\n")
+	}
+	content := b.String()
+	genConfigs.Lock()
+	defer genConfigs.Unlock()
+	// Files might be removed by tmp cleaners, and are written again in this case.
+	if p, ok := genConfigs.paths[content]; ok {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	if _, err := os.Stat(genConfigs.dir); err != nil {
+		dir, err := os.MkdirTemp("", "atlasexec-")
+		if err != nil {
+			return "", fmt.Errorf("atlasexec: creating project file directory: %w", err)
+		}
+		genConfigs.dir, genConfigs.paths = dir, make(map[string]string)
+	}
+	sum := sha256.Sum256([]byte(content))
+	path := filepath.Join(genConfigs.dir, "atlas-"+hex.EncodeToString(sum[:8])+".hcl")
+	if err := writeFileAtomic(path, content); err != nil {
+		return "", fmt.Errorf("atlasexec: writing project file: %w", err)
+	}
+	genConfigs.paths[content] = path
+	return path, nil
+}
+
+// writeFileAtomic writes the content to the given path, using a temporary file
+// and a rename, to not expose partial files to concurrent processes.
+func writeFileAtomic(path, content string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
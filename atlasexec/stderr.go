@@ -0,0 +1,116 @@
+package atlasexec
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// StderrEvent is a line written by the atlas-cli to its stderr.
+// Credentials are masked in the line, see WithSecretVars.
+type StderrEvent struct {
+	Time    time.Time  // Time the line was written.
+	Command string     // Name of the command, e.g. "schema apply".
+	Level   slog.Level // Severity of the line, guessed from its content.
+	Line    string     // The line, without the trailing newline.
+}
+
+// WithStderrFunc configures the Client to call fn for every line written to stderr by
+// the atlas-cli, as it is written. It allows reporting warnings of long-running commands
+// before they complete. The stderr is still returned in the Error of failed commands.
+// The function is called sequentially for each command, and replaces the one set by
+// previous calls to WithStderrFunc or WithStderrHandler.
+func WithStderrFunc(fn func(context.Context, *StderrEvent)) ClientOption {
+	return func(c *Client) {
+		c.onStderr = fn
+	}
+}
+
+// WithStderrHandler configures the Client to log the lines written to stderr by the
+// atlas-cli using the given handler. Records have the level of the event, and the
+// name of the command in the "command" attribute. See WithStderrFunc for details.
+//
+//	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithStderrHandler(slog.Default().Handler()))
+func WithStderrHandler(h slog.Handler) ClientOption {
+	return WithStderrFunc(func(ctx context.Context, e *StderrEvent) {
+		if !h.Enabled(ctx, e.Level) {
+			return
+		}
+		r := slog.NewRecord(e.Time, e.Level, e.Line, 0)
+		r.AddAttrs(slog.String("command", e.Command))
+		_ = h.Handle(ctx, r)
+	})
+}
+
+// stderrLevel guesses the severity of a line written to stderr.
+func stderrLevel(line string) slog.Level {
+	l := strings.ToLower(strings.TrimSpace(line))
+	switch {
+	case strings.HasPrefix(l, "error"), strings.HasPrefix(l, "fatal"), strings.HasPrefix(l, "panic"):
+		return slog.LevelError
+	case strings.HasPrefix(l, "warn"), strings.Contains(l, "deprecated"):
+		return slog.LevelWarn
+	case strings.HasPrefix(l, "debug"):
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// stderrWriter is an io.Writer that reports the written lines as StderrEvents.
+type stderrWriter struct {
+	ctx     context.Context
+	command string
+	fn      func(context.Context, *StderrEvent)
+	redact  *redactor
+	buf     []byte
+}
+
+// stderrEvents returns the writer that reports the stderr of the given invocation, and
+// a function to flush it once the command exited. The returned writer is nil if no
+// stderr function was configured.
+func (c *Client) stderrEvents(ctx context.Context, inv *Invocation) (io.Writer, func()) {
+	if c.onStderr == nil {
+		return nil, func() {}
+	}
+	w := &stderrWriter{ctx: ctx, command: commandName(inv.Args), fn: c.onStderr, redact: c.redactor(inv.Args, inv.Env)}
+	return w, w.flush
+}
+
+// Write implements the io.Writer interface.
+func (w *stderrWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush reports the last line, in case it was not terminated by a newline.
+func (w *stderrWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *stderrWriter) emit(line string) {
+	line = strings.TrimSuffix(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.fn(w.ctx, &StderrEvent{
+		Time:    time.Now(),
+		Command: w.command,
+		Level:   stderrLevel(line),
+		Line:    w.redact.text(line),
+	})
+}
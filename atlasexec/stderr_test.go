@@ -0,0 +1,71 @@
+package atlasexec_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStderrFunc(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []*atlasexec.StderrEvent
+	)
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithStderrFunc(func(_ context.Context, e *atlasexec.StderrEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}))
+	ex.On("schema", "apply", "--format", "{{ json . }}", "--env", "prod").
+		Stdout(`{"Driver":"mysql"}`).
+		Stderr("Warning: the column \"name\" is deprecated\n\nApplying changes\r\nerror: unexpected\nDEBUG: done").
+		ExitCode(0)
+	_, err := c.SchemaApply(context.Background(), &atlasexec.SchemaApplyParams{Env: "prod"})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	for _, e := range events {
+		require.Equal(t, "schema apply", e.Command)
+		require.False(t, e.Time.IsZero())
+	}
+	require.Equal(t, `Warning: the column "name" is deprecated`, events[0].Line)
+	require.Equal(t, slog.LevelWarn, events[0].Level)
+	require.Equal(t, "Applying changes", events[1].Line)
+	require.Equal(t, slog.LevelInfo, events[1].Level)
+	require.Equal(t, slog.LevelError, events[2].Level)
+	require.Equal(t, "DEBUG: done", events[3].Line, "unterminated lines are reported")
+	require.Equal(t, slog.LevelDebug, events[3].Level)
+
+	// Stderr of streams is reported as well.
+	events = nil
+	ex.On("copilot", "-q", "hi").Stdout(`{"type":"message","content":"hello"}`).Stderr("Warning: slow\n").ExitCode(0)
+	s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+	require.NoError(t, err)
+	_, err = atlasexec.Collect(s)
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 1)
+	require.Equal(t, "copilot", events[0].Command)
+	require.Equal(t, "Warning: slow", events[0].Line)
+}
+
+func TestWithStderrHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithStderrHandler(h))
+	ex.On("migrate", "lint", "--format", "{{ json . }}").Stdout(`{"Env":{}}`).Stderr("Applying\nWarning: destructive change\n").ExitCode(0)
+	_, err := c.MigrateLint(context.Background(), &atlasexec.MigrateLintParams{})
+	require.NoError(t, err)
+	var r map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &r), "only a single record is expected")
+	require.Equal(t, "WARN", r["level"])
+	require.Equal(t, "Warning: destructive change", r["msg"])
+	require.Equal(t, "migrate lint", r["command"])
+}
@@ -0,0 +1,176 @@
+package atlasexec
+
+import "sync"
+
+// Collect reads all items of the stream, and returns them along with the
+// final error of the stream. The stream is closed when Collect returns.
+func Collect[T any](s Stream[T]) ([]T, error) {
+	var items []T
+	for s.Next() {
+		v, err := s.Current()
+		if err != nil {
+			s.Close()
+			return items, err
+		}
+		items = append(items, v)
+	}
+	return items, s.Close()
+}
+
+// Map returns a stream that converts the items of s using fn. An error returned
+// by fn is reported by Current, and by Err in case the underlying stream succeeded.
+func Map[T, U any](s Stream[T], fn func(T) (U, error)) Stream[U] {
+	return &mapStream[T, U]{s: s, fn: fn}
+}
+
+// Filter returns a stream that yields only the items of s for which keep returns true.
+// Items that failed to be read are always yielded, so their error is not lost.
+func Filter[T any](s Stream[T], keep func(T) bool) Stream[T] {
+	return &filterStream[T]{Stream: s, keep: keep}
+}
+
+// Tee splits the given stream into two streams that yield the same items, for
+// example, to render the progress of a command while recording its output. Items
+// are buffered until both streams read them. The underlying stream is closed
+// once both streams were closed, and both report its final error.
+func Tee[T any](s Stream[T]) (Stream[T], Stream[T]) {
+	t := &tee[T]{s: s, queues: make([][]teeItem[T], 2), closed: make([]bool, 2)}
+	return &teeStream[T]{t: t, i: 0}, &teeStream[T]{t: t, i: 1}
+}
+
+type mapStream[T, U any] struct {
+	s   Stream[T]
+	fn  func(T) (U, error)
+	cur *U
+	err error
+}
+
+// Next advances the stream to the next item.
+func (s *mapStream[T, U]) Next() bool {
+	s.cur = nil
+	return s.err == nil && s.s.Next()
+}
+
+// Current returns the current item, converted using the map function.
+func (s *mapStream[T, U]) Current() (U, error) {
+	var zero U
+	if s.err != nil {
+		return zero, s.err
+	}
+	if s.cur == nil {
+		v, err := s.s.Current()
+		if err != nil {
+			return zero, err
+		}
+		u, err := s.fn(v)
+		if err != nil {
+			s.err = err
+			return zero, err
+		}
+		s.cur = &u
+	}
+	return *s.cur, nil
+}
+
+// Err returns the error of the underlying stream, or of the map function.
+func (s *mapStream[T, U]) Err() error {
+	if err := s.s.Err(); err != nil {
+		return err
+	}
+	return s.err
+}
+
+// Close closes the underlying stream.
+func (s *mapStream[T, U]) Close() error {
+	if err := s.s.Close(); err != nil {
+		return err
+	}
+	return s.err
+}
+
+type filterStream[T any] struct {
+	Stream[T]
+	keep func(T) bool
+}
+
+// Next advances the stream to the next item that matches the filter.
+func (s *filterStream[T]) Next() bool {
+	for s.Stream.Next() {
+		v, err := s.Stream.Current()
+		if err != nil || s.keep(v) {
+			return true
+		}
+	}
+	return false
+}
+
+type (
+	// tee holds the state shared between the streams returned by Tee.
+	tee[T any] struct {
+		mu     sync.Mutex
+		s      Stream[T]
+		queues [][]teeItem[T]
+		closed []bool
+		eof    bool
+	}
+	teeItem[T any] struct {
+		v   T
+		err error
+	}
+	teeStream[T any] struct {
+		t   *tee[T]
+		i   int
+		cur teeItem[T]
+	}
+)
+
+// Next advances the stream to the next item.
+func (s *teeStream[T]) Next() bool {
+	t := s.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed[s.i] {
+		return false
+	}
+	if len(t.queues[s.i]) == 0 {
+		if t.eof || !t.s.Next() {
+			t.eof = true
+			return false
+		}
+		v, err := t.s.Current()
+		for i := range t.queues {
+			if !t.closed[i] {
+				t.queues[i] = append(t.queues[i], teeItem[T]{v: v, err: err})
+			}
+		}
+	}
+	s.cur, t.queues[s.i] = t.queues[s.i][0], t.queues[s.i][1:]
+	return true
+}
+
+// Current returns the current item from the stream.
+func (s *teeStream[T]) Current() (T, error) {
+	return s.cur.v, s.cur.err
+}
+
+// Err returns the error of the underlying stream.
+func (s *teeStream[T]) Err() error {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	return s.t.s.Err()
+}
+
+// Close closes the stream. The underlying stream is closed
+// once all streams returned by Tee were closed.
+func (s *teeStream[T]) Close() error {
+	t := s.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed[s.i], t.queues[s.i] = true, nil
+	for _, c := range t.closed {
+		if !c {
+			return t.s.Err()
+		}
+	}
+	return t.s.Close()
+}
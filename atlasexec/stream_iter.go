@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package atlasexec
+
+import "iter"
+
+// Seq returns an iterator over the items of the stream, to be used with range:
+//
+//	for m := range atlasexec.Seq(s) {
+//		fmt.Println(m.Content)
+//	}
+//	if err := s.Err(); err != nil {
+//		return err
+//	}
+//
+// The iteration stops at the first item that failed to be read. As errors are
+// not yielded, the caller must check the Err method of the stream after the
+// loop, or use Seq2 instead. Breaking out of the loop closes the stream.
+func Seq[T any](s Stream[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s.Next() {
+			v, err := s.Current()
+			if err != nil {
+				s.Close()
+				return
+			}
+			if !yield(v) {
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+// Seq2 returns an iterator over the items of the stream and their errors, to be used with range:
+//
+//	for m, err := range atlasexec.Seq2(s) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(m.Content)
+//	}
+//
+// Unlike Seq, the final error of the stream is yielded as the last pair,
+// and the iteration stops after the first error. Breaking out of the
+// loop closes the stream.
+func Seq2[T any](s Stream[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for s.Next() {
+			v, err := s.Current()
+			if err != nil {
+				s.Close()
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				s.Close()
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
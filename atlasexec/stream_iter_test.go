@@ -0,0 +1,59 @@
+//go:build go1.23
+
+package atlasexec_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeq(t *testing.T) {
+	var vs []string
+	s := copilotStream(t, "", "a", "b", "c")
+	for m := range atlasexec.Seq(s) {
+		vs = append(vs, m.Content)
+	}
+	require.NoError(t, s.Err())
+	require.Equal(t, []string{"a", "b", "c"}, vs)
+
+	// Breaking out of the loop closes the stream.
+	vs = nil
+	s = copilotStream(t, "", "a", "b", "c")
+	for m := range atlasexec.Seq(s) {
+		vs = append(vs, m.Content)
+		break
+	}
+	require.Equal(t, []string{"a"}, vs)
+	require.False(t, s.Next())
+
+	s = copilotStream(t, "Error: boom", "a")
+	for range atlasexec.Seq(s) {
+	}
+	require.EqualError(t, s.Err(), "Error: boom")
+}
+
+func TestSeq2(t *testing.T) {
+	var (
+		vs   []string
+		errs []error
+	)
+	for m, err := range atlasexec.Seq2(copilotStream(t, "Error: boom", "a", "b")) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		vs = append(vs, m.Content)
+	}
+	require.Equal(t, []string{"a", "b"}, vs)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "Error: boom")
+
+	vs, errs = nil, nil
+	for v, err := range atlasexec.Seq2(atlasexec.Map(copilotStream(t, "", "a", "b"), content)) {
+		require.NoError(t, err)
+		vs = append(vs, v)
+	}
+	require.Equal(t, []string{"a", "b"}, vs)
+}
@@ -0,0 +1,108 @@
+package atlasexec_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+// copilotStream returns a Copilot stream that yields the given messages, and fails with stderr if not empty.
+func copilotStream(t *testing.T, stderr string, contents ...string) atlasexec.Stream[*atlasexec.CopilotMessage] {
+	t.Helper()
+	c, ex := atlasexectest.NewClient(t)
+	var out strings.Builder
+	for _, m := range contents {
+		out.WriteString(`{"type":"message","content":"` + m + `"}` + "\n")
+	}
+	ex.On("copilot", "-q", "hi").Stdout(out.String()).Stderr(stderr)
+	s, err := c.CopilotStream(context.Background(), &atlasexec.CopilotParams{Prompt: "hi"})
+	require.NoError(t, err)
+	return s
+}
+
+func content(m *atlasexec.CopilotMessage) (string, error) {
+	return m.Content, nil
+}
+
+func TestCollect(t *testing.T) {
+	ms, err := atlasexec.Collect(copilotStream(t, "", "a", "b"))
+	require.NoError(t, err)
+	require.Len(t, ms, 2)
+	require.Equal(t, "b", ms[1].Content)
+
+	// The final error of the stream is returned.
+	ms, err = atlasexec.Collect(copilotStream(t, "Error: boom", "a"))
+	require.EqualError(t, err, "Error: boom")
+	require.Len(t, ms, 1)
+}
+
+func TestMap(t *testing.T) {
+	s := atlasexec.Map(copilotStream(t, "", "a", "b"), content)
+	vs, err := atlasexec.Collect(s)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, vs)
+
+	// Errors of the map function are reported by Current and Err.
+	s = atlasexec.Map(copilotStream(t, "", "a", "b"), func(m *atlasexec.CopilotMessage) (string, error) {
+		if m.Content == "b" {
+			return "", errors.New("unexpected b")
+		}
+		return m.Content, nil
+	})
+	vs, err = atlasexec.Collect(s)
+	require.EqualError(t, err, "unexpected b")
+	require.Equal(t, []string{"a"}, vs)
+	require.EqualError(t, s.Err(), "unexpected b")
+
+	// Errors of the underlying stream take precedence.
+	s = atlasexec.Map(copilotStream(t, "Error: boom", "a"), content)
+	vs, err = atlasexec.Collect(s)
+	require.EqualError(t, err, "Error: boom")
+	require.Equal(t, []string{"a"}, vs)
+}
+
+func TestFilter(t *testing.T) {
+	s := atlasexec.Filter(atlasexec.Map(copilotStream(t, "", "a", "bb", "c", "dd"), content), func(s string) bool {
+		return len(s) == 2
+	})
+	vs, err := atlasexec.Collect(s)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bb", "dd"}, vs)
+
+	s = atlasexec.Filter(atlasexec.Map(copilotStream(t, "Error: boom", "a"), content), func(string) bool {
+		return false
+	})
+	vs, err = atlasexec.Collect(s)
+	require.EqualError(t, err, "Error: boom")
+	require.Empty(t, vs)
+}
+
+func TestTee(t *testing.T) {
+	s1, s2 := atlasexec.Tee(atlasexec.Map(copilotStream(t, "", "a", "b", "c"), content))
+	// Streams are consumed in different paces.
+	require.True(t, s1.Next())
+	require.True(t, s1.Next())
+	v, err := s1.Current()
+	require.NoError(t, err)
+	require.Equal(t, "b", v)
+	vs, err := atlasexec.Collect(s2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, vs)
+	vs, err = atlasexec.Collect(s1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, vs)
+
+	// Both streams report the final error.
+	s1, s2 = atlasexec.Tee(atlasexec.Map(copilotStream(t, "Error: boom", "a"), content))
+	vs, err = atlasexec.Collect(s1)
+	require.EqualError(t, err, "Error: boom")
+	require.Equal(t, []string{"a"}, vs)
+	vs, err = atlasexec.Collect(s2)
+	require.EqualError(t, err, "Error: boom")
+	require.Equal(t, []string{"a"}, vs)
+}
@@ -0,0 +1,384 @@
+package atlasexec
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// InvalidParamsError is an error type for invalid parameters.
+// It reports all the violations found in the parameters of a command.
+type InvalidParamsError struct {
+	cmd  string
+	msgs []string
+}
+
+// Error returns the error message.
+func (e *InvalidParamsError) Error() string {
+	return fmt.Sprintf("atlasexec: command %q has invalid parameters: %v", e.cmd, strings.Join(e.msgs, "; "))
+}
+
+// Command returns the name of the command, e.g. "migrate apply".
+func (e *InvalidParamsError) Command() string {
+	return e.cmd
+}
+
+// Violations returns the violations found in the parameters.
+func (e *InvalidParamsError) Violations() []string {
+	return slices.Clone(e.msgs)
+}
+
+// validator collects the violations of the parameters of a command.
+type validator struct {
+	cmd  string
+	msgs []string
+}
+
+// check records the given violation if cond is false.
+func (v *validator) check(cond bool, format string, args ...any) {
+	if !cond {
+		v.msgs = append(v.msgs, fmt.Sprintf(format, args...))
+	}
+}
+
+// required records a violation if the value of the given flag is empty.
+func (v *validator) required(flag, value string) {
+	v.check(value != "", "missing required flag %s", flag)
+}
+
+// exclusive records a violation if more than one of the given flags is set.
+func (v *validator) exclusive(flags map[string]bool) {
+	var set []string
+	for f, ok := range flags {
+		if ok {
+			set = append(set, f)
+		}
+	}
+	if len(set) > 1 {
+		slices.Sort(set)
+		v.msgs = append(v.msgs, fmt.Sprintf("flags %s are mutually exclusive", strings.Join(set, ", ")))
+	}
+}
+
+// oneOf records a violation if the value of the given flag is set,
+// and it is not one of the allowed values.
+func oneOf[T ~string](v *validator, flag string, value T, allowed []T) {
+	v.check(value == "" || slices.Contains(allowed, value), "invalid value %q for flag %s, expected one of: %s", value, flag, joinValues(allowed))
+}
+
+// err returns an InvalidParamsError holding all
+// violations, or nil if no violations were found.
+func (v *validator) err() error {
+	if len(v.msgs) == 0 {
+		return nil
+	}
+	return &InvalidParamsError{cmd: v.cmd, msgs: v.msgs}
+}
+
+// Supported values of the flags.
+var (
+	dirFormats = []DirFormat{
+		DirFormatAtlas, DirFormatGolangMigrate, DirFormatGoose,
+		DirFormatFlyway, DirFormatLiquibase, DirFormatDBMate,
+	}
+	migrateTxModes = []TxMode{TxModeFile, TxModeAll, TxModeNone}
+	schemaTxModes  = []TxMode{TxModeFile, TxModeNone}
+	execOrders     = []MigrateExecOrder{ExecOrderLinear, ExecOrderLinearSkip, ExecOrderNonLinear}
+)
+
+// ParseTxMode parses the given string into a TxMode.
+// Note that 'schema apply' does not support TxModeAll.
+func ParseTxMode(s string) (TxMode, error) {
+	return parseValue("transaction mode", s, migrateTxModes)
+}
+
+// ParseDirFormat parses the given string into a DirFormat.
+func ParseDirFormat(s string) (DirFormat, error) {
+	return parseValue("directory format", s, dirFormats)
+}
+
+// ParseMigrateExecOrder parses the given string into a MigrateExecOrder.
+func ParseMigrateExecOrder(s string) (MigrateExecOrder, error) {
+	return parseValue("execution order", s, execOrders)
+}
+
+// ParseLintFormat parses the given string into a LintFormat. Besides the
+// predefined values, any Go template with a valid syntax is accepted.
+func ParseLintFormat(s string) (LintFormat, error) {
+	if err := parseTemplate(s); err != nil {
+		return "", fmt.Errorf("atlasexec: invalid lint format %q: %w", s, err)
+	}
+	return LintFormat(s), nil
+}
+
+// lintFormat records a violation if the given lint format is not a valid template.
+func lintFormat(v *validator, f LintFormat) {
+	if f == "" {
+		return
+	}
+	if err := parseTemplate(string(f)); err != nil {
+		v.check(false, "invalid value %q for flag --format: %v", f, err)
+	}
+}
+
+// parseTemplate checks the syntax of the given Go template. Functions are not
+// checked, as they are defined by the atlas-cli, e.g. json and sql.
+func parseTemplate(s string) error {
+	t := parse.New("format")
+	t.Mode = parse.SkipFuncCheck
+	_, err := t.Parse(s, "", "", make(map[string]*parse.Tree))
+	return err
+}
+
+// parseValue returns the value matching the given string.
+func parseValue[T ~string](name, s string, values []T) (T, error) {
+	if i := slices.Index(values, T(s)); i != -1 {
+		return values[i], nil
+	}
+	return "", fmt.Errorf("atlasexec: unknown %s %q, expected one of: %s", name, s, joinValues(values))
+}
+
+// joinValues joins the given values into a comma-separated list.
+func joinValues[T ~string](values []T) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Validate reports if the parameters are invalid.
+func (p *LoginParams) Validate() error {
+	v := &validator{cmd: "login"}
+	v.required("--token", p.Token)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *WhoAmIParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *CopilotParams) Validate() error {
+	v := &validator{cmd: "copilot"}
+	v.required("-q", p.Prompt)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateApplyParams) Validate() error {
+	v := &validator{cmd: "migrate apply"}
+	oneOf(v, "--tx-mode", p.TxMode, migrateTxModes)
+	oneOf(v, "--exec-order", p.ExecOrder, execOrders)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateDownParams) Validate() error {
+	v := &validator{cmd: "migrate down"}
+	v.exclusive(map[string]bool{
+		"--to-version": p.ToVersion != "",
+		"--to-tag":     p.ToTag != "",
+		"[amount]":     p.Amount > 0,
+	})
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigratePushParams) Validate() error {
+	v := &validator{cmd: "migrate push"}
+	v.check(p.Name != "", "directory name cannot be empty")
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateLintParams) Validate() error {
+	v := &validator{cmd: "migrate lint"}
+	lintFormat(v, p.Format)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateHashParams) Validate() error {
+	v := &validator{cmd: "migrate hash"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateRebaseParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateTestParams) Validate() error {
+	v := &validator{cmd: "migrate test"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateStatusParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateDiffParams) Validate() error {
+	v := &validator{cmd: "migrate diff"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateNewParams) Validate() error {
+	v := &validator{cmd: "migrate new"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	v.check(p.Content == "" || p.DirFormat == "" || p.DirFormat == DirFormatAtlas, "content is supported only for the atlas directory format")
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateImportParams) Validate() error {
+	v := &validator{cmd: "migrate import"}
+	v.check(p.FromURL != "" || p.FromFS != nil, "missing directory to import")
+	v.check(p.FromURL == "" || p.FromFS == nil, "FromURL and FromFS are mutually exclusive")
+	v.check(p.FromFormat == "" || slices.Contains(dirFormats, p.FromFormat), "invalid format %q of the imported directory, expected one of: %s", p.FromFormat, joinValues(dirFormats))
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateCheckpointParams) Validate() error {
+	v := &validator{cmd: "migrate checkpoint"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateSetParams) Validate() error {
+	v := &validator{cmd: "migrate set"}
+	v.check(p.Version != "", "missing version to set")
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateValidateParams) Validate() error {
+	v := &validator{cmd: "migrate validate"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *MigrateLsParams) Validate() error {
+	v := &validator{cmd: "migrate ls"}
+	oneOf(v, "--dir-format", p.DirFormat, dirFormats)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPushParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaApplyParams) Validate() error {
+	v := &validator{cmd: "schema apply"}
+	oneOf(v, "--tx-mode", p.TxMode, schemaTxModes)
+	v.exclusive(map[string]bool{
+		"--dry-run":      p.DryRun,
+		"--auto-approve": p.AutoApprove,
+	})
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaInspectParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaDiffParams) Validate() error {
+	v := &validator{cmd: "schema diff"}
+	// The states can be defined by the env of the project file.
+	if p.Env == "" {
+		v.check(len(p.From) > 0, "missing required flag --from")
+		v.check(len(p.To) > 0, "missing required flag --to")
+	}
+	// The output is parsed as SQL statements.
+	v.check(p.Format == "" || reSQLFormat.MatchString(p.Format), "invalid value %q for flag --format, expected a template using the sql function", p.Format)
+	return v.err()
+}
+
+// reSQLFormat matches templates that use the sql function, e.g. `{{ sql . "  " }}`.
+var reSQLFormat = regexp.MustCompile(`\{\{-?\s*sql\s`)
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaTestParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanListParams) Validate() error {
+	return nil
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanPushParams) Validate() error {
+	v := &validator{cmd: "schema plan push"}
+	v.required("--file", p.File)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanPullParams) Validate() error {
+	v := &validator{cmd: "schema plan pull"}
+	v.required("--url", p.URL)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanLintParams) Validate() error {
+	v := &validator{cmd: "schema plan lint"}
+	v.required("--file", p.File)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanValidateParams) Validate() error {
+	v := &validator{cmd: "schema plan validate"}
+	v.required("--file", p.File)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaPlanApproveParams) Validate() error {
+	v := &validator{cmd: "schema plan approve"}
+	v.required("--url", p.URL)
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaCleanParams) Validate() error {
+	v := &validator{cmd: "schema clean"}
+	v.exclusive(map[string]bool{
+		"--dry-run":      p.DryRun,
+		"--auto-approve": p.AutoApprove,
+	})
+	return v.err()
+}
+
+// Validate reports if the parameters are invalid.
+func (p *SchemaLintParams) Validate() error {
+	v := &validator{cmd: "schema lint"}
+	lintFormat(v, p.Format)
+	return v.err()
+}
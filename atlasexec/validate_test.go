@@ -0,0 +1,128 @@
+package atlasexec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		params interface{ Validate() error }
+		cmd    string
+		want   []string
+	}{
+		{
+			name:   "migrate apply",
+			params: &atlasexec.MigrateApplyParams{TxMode: "fil", ExecOrder: "linear"},
+			cmd:    "migrate apply",
+			want:   []string{`invalid value "fil" for flag --tx-mode, expected one of: file, all, none`},
+		},
+		{
+			name:   "migrate down",
+			params: &atlasexec.MigrateDownParams{ToVersion: "1", ToTag: "v1"},
+			cmd:    "migrate down",
+			want:   []string{"flags --to-tag, --to-version are mutually exclusive"},
+		},
+		{
+			name:   "migrate push",
+			params: &atlasexec.MigratePushParams{DirFormat: "atlass"},
+			cmd:    "migrate push",
+			want: []string{
+				"directory name cannot be empty",
+				`invalid value "atlass" for flag --dir-format, expected one of: atlas, golang-migrate, goose, flyway, liquibase, dbmate`,
+			},
+		},
+		{
+			name:   "migrate lint",
+			params: &atlasexec.MigrateLintParams{Format: "{{ range .Files }}"},
+			cmd:    "migrate lint",
+			want:   []string{`invalid value "{{ range .Files }}" for flag --format: template: format:1: unexpected EOF`},
+		},
+		{
+			name:   "schema apply",
+			params: &atlasexec.SchemaApplyParams{DryRun: true, AutoApprove: true, TxMode: "all"},
+			cmd:    "schema apply",
+			want: []string{
+				`invalid value "all" for flag --tx-mode, expected one of: file, none`,
+				"flags --auto-approve, --dry-run are mutually exclusive",
+			},
+		},
+		{
+			name:   "schema diff",
+			params: &atlasexec.SchemaDiffParams{Env: "prod", Format: "{{ json . }}"},
+			cmd:    "schema diff",
+			want:   []string{`invalid value "{{ json . }}" for flag --format, expected a template using the sql function`},
+		},
+		{
+			name:   "schema plan approve",
+			params: &atlasexec.SchemaPlanApproveParams{},
+			cmd:    "schema plan approve",
+			want:   []string{"missing required flag --url"},
+		},
+		{
+			name:   "login",
+			params: &atlasexec.LoginParams{},
+			cmd:    "login",
+			want:   []string{"missing required flag --token"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var perr *atlasexec.InvalidParamsError
+			require.True(t, errors.As(tt.params.Validate(), &perr))
+			require.Equal(t, tt.cmd, perr.Command())
+			require.Equal(t, tt.want, perr.Violations())
+		})
+	}
+	require.NoError(t, (&atlasexec.MigrateDownParams{Amount: 2}).Validate())
+	require.NoError(t, (&atlasexec.MigrateApplyParams{TxMode: atlasexec.TxModeAll, ExecOrder: atlasexec.ExecOrderNonLinear}).Validate())
+	// Flag combinations are left for the atlas-cli to check.
+	require.NoError(t, (&atlasexec.MigrateRebaseParams{}).Validate())
+	require.NoError(t, (&atlasexec.SchemaPlanParams{DryRun: true, Push: true}).Validate())
+	require.NoError(t, (&atlasexec.MigrateLintParams{Format: atlasexec.LintFormatURL}).Validate())
+	require.NoError(t, (&atlasexec.SchemaLintParams{Format: "{{ sql . }}"}).Validate())
+	require.NoError(t, (&atlasexec.SchemaDiffParams{Env: "prod", Format: `{{- sql . "  " -}}`}).Validate())
+}
+
+func TestParams_ValidateBeforeRun(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t)
+	_, err := c.MigrateDown(context.Background(), &atlasexec.MigrateDownParams{ToVersion: "1", Amount: 1})
+	require.EqualError(t, err, `atlasexec: command "migrate down" has invalid parameters: flags --to-version, [amount] are mutually exclusive`)
+	_, err = c.SchemaPlanPull(context.Background(), &atlasexec.SchemaPlanPullParams{})
+	require.EqualError(t, err, `atlasexec: command "schema plan pull" has invalid parameters: missing required flag --url`)
+	require.Empty(t, ex.Invocations(), "atlas-cli is not executed")
+}
+
+func TestParseValues(t *testing.T) {
+	m, err := atlasexec.ParseTxMode("all")
+	require.NoError(t, err)
+	require.Equal(t, atlasexec.TxModeAll, m)
+	_, err = atlasexec.ParseTxMode("al")
+	require.EqualError(t, err, `atlasexec: unknown transaction mode "al", expected one of: file, all, none`)
+
+	f, err := atlasexec.ParseDirFormat("golang-migrate")
+	require.NoError(t, err)
+	require.Equal(t, atlasexec.DirFormatGolangMigrate, f)
+	_, err = atlasexec.ParseDirFormat("")
+	require.EqualError(t, err, `atlasexec: unknown directory format "", expected one of: atlas, golang-migrate, goose, flyway, liquibase, dbmate`)
+
+	o, err := atlasexec.ParseMigrateExecOrder("linear-skip")
+	require.NoError(t, err)
+	require.Equal(t, atlasexec.ExecOrderLinearSkip, o)
+	_, err = atlasexec.ParseMigrateExecOrder("nonlinear")
+	require.Error(t, err)
+
+	l, err := atlasexec.ParseLintFormat("{{ json . }}")
+	require.NoError(t, err)
+	require.Equal(t, atlasexec.LintFormatJSON, l)
+	l, err = atlasexec.ParseLintFormat("{{ range .Files }}{{ .Name }}{{ end }}")
+	require.NoError(t, err)
+	require.Equal(t, atlasexec.LintFormat("{{ range .Files }}{{ .Name }}{{ end }}"), l)
+	_, err = atlasexec.ParseLintFormat("{{ json . ")
+	require.ErrorContains(t, err, `atlasexec: invalid lint format "{{ json . ":`)
+}
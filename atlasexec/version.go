@@ -0,0 +1,204 @@
+package atlasexec
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// UnsupportedVersionError is returned when the command is not supported
+	// by the version of the atlas-cli used by the Client.
+	UnsupportedVersionError struct {
+		Command  string   // Name of the command, e.g. "schema lint".
+		Required string   // Minimum version required by the command.
+		Actual   *Version // Version of the atlas-cli.
+	}
+	// versionProbe caches the version of the atlas-cli.
+	// It is shared between clients derived using With.
+	versionProbe struct {
+		mu   sync.Mutex
+		v    *Version
+		call *probeCall // In-flight probe, if exists.
+	}
+	// probeCall is a version probe shared by concurrent commands.
+	probeCall struct {
+		done chan struct{}
+		v    *Version
+		err  error
+	}
+)
+
+// ErrUnsupportedVersion is matched by errors.Is for UnsupportedVersionError.
+var ErrUnsupportedVersion = errors.New("atlasexec: unsupported atlas-cli version")
+
+// capabilities holds the minimum atlas-cli version required by commands. Sub-commands
+// that are not listed inherit the requirement of their parent, e.g. "schema plan lint".
+// Each version is the release that introduced the command:
+//
+//   - migrate test, schema test: https://github.com/ariga/atlas/releases/tag/v0.17.0
+//   - schema plan:               https://github.com/ariga/atlas/releases/tag/v0.25.0
+//   - schema lint:               https://github.com/ariga/atlas/releases/tag/v0.31.0
+var capabilities = map[string]string{
+	"migrate test": "v0.17.0",
+	"schema test":  "v0.17.0",
+	"schema plan":  "v0.25.0",
+	"schema lint":  "v0.31.0",
+}
+
+// WithVersionCheck configures the Client to verify that commands are supported by the
+// atlas-cli before executing them. The version is probed once, on the first command
+// that has a version requirement, and an UnsupportedVersionError is returned for
+// commands that require a newer version. The probed version is shared by the clients
+// derived from the configured client.
+func WithVersionCheck() ClientOption {
+	return func(c *Client) {
+		if c.version == nil {
+			c.version = &versionProbe{}
+		}
+	}
+}
+
+// ParseVersion parses a version in the format printed by 'atlas version',
+// e.g. "atlas version v0.32.1-abcdef-canary", or a bare one such as "v0.32.0".
+func ParseVersion(s string) (*Version, error) {
+	s = strings.TrimSpace(s)
+	out := s
+	if !strings.HasPrefix(out, "atlas version ") {
+		if !strings.HasPrefix(out, "v") {
+			out = "v" + out
+		}
+		out = "atlas version " + out
+	}
+	v := reVersion.FindStringSubmatch(out)
+	if v == nil {
+		return nil, fmt.Errorf("atlasexec: invalid version %q", s)
+	}
+	var sha string
+	if len(v) > 2 {
+		sha = v[2]
+	}
+	return &Version{
+		Version: v[1],
+		SHA:     sha,
+		Canary:  strings.Contains(out, "canary"),
+	}, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or greater than w.
+// Releases are compared by their numeric components, and canary builds are ordered
+// before the release of the same version. The SHA of the build is ignored.
+func (v Version) Compare(w Version) int {
+	if c := compareNums(v.Version, w.Version); c != 0 {
+		return c
+	}
+	switch {
+	case v.Canary == w.Canary:
+		return 0
+	case v.Canary:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// AtLeast reports whether v is greater than or equal to the given version, e.g. "v0.30.0".
+// Unlike Compare, canary builds are considered to satisfy the release of the same version,
+// as they already contain its features. It returns false if min is not a valid version.
+func (v Version) AtLeast(min string) bool {
+	m, err := ParseVersion(min)
+	if err != nil {
+		return false
+	}
+	return compareNums(v.Version, m.Version) >= 0
+}
+
+// compareNums compares two versions in the format of "X.Y.Z".
+func compareNums(v, w string) int {
+	vs, ws := strings.Split(v, "."), strings.Split(w, ".")
+	for i := 0; i < len(vs) || i < len(ws); i++ {
+		var a, b int
+		if i < len(vs) {
+			a, _ = strconv.Atoi(vs[i])
+		}
+		if i < len(ws) {
+			b, _ = strconv.Atoi(ws[i])
+		}
+		if c := cmp.Compare(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// checkVersion returns an UnsupportedVersionError if the command invoked by
+// the given arguments is not supported by the atlas-cli version of the client.
+func (c *Client) checkVersion(ctx context.Context, args []string) error {
+	if c.version == nil {
+		return nil
+	}
+	name := commandName(args)
+	required, ok := capabilities[name]
+	for !ok && strings.Contains(name, " ") {
+		name = name[:strings.LastIndex(name, " ")]
+		required, ok = capabilities[name]
+	}
+	if !ok {
+		return nil
+	}
+	v, err := c.version.get(ctx, c)
+	if err != nil {
+		return fmt.Errorf("atlasexec: probing atlas-cli version: %w", err)
+	}
+	if !v.AtLeast(required) {
+		return &UnsupportedVersionError{Command: commandName(args), Required: required, Actual: v}
+	}
+	return nil
+}
+
+// get returns the cached version, or probes it using the given client. The probe runs
+// without holding the lock, and is shared by concurrent callers, which stop waiting for
+// it once their context is done. Failed probes are not cached, and are retried on the
+// next call.
+func (p *versionProbe) get(ctx context.Context, c *Client) (*Version, error) {
+	p.mu.Lock()
+	if p.v != nil {
+		defer p.mu.Unlock()
+		return p.v, nil
+	}
+	if call := p.call; call != nil {
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.v, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &probeCall{done: make(chan struct{})}
+	p.call = call
+	p.mu.Unlock()
+	call.v, call.err = c.Version(ctx)
+	p.mu.Lock()
+	if call.err == nil {
+		p.v = call.v
+	}
+	p.call = nil
+	p.mu.Unlock()
+	close(call.done)
+	return call.v, call.err
+}
+
+// Error implements the error interface.
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("atlasexec: command %q requires atlas-cli %s or later, but v%s is used", e.Command, e.Required, e.Actual.Version)
+}
+
+// Is reports whether the target is ErrUnsupportedVersion.
+func (e *UnsupportedVersionError) Is(target error) bool {
+	return target == ErrUnsupportedVersion
+}
@@ -0,0 +1,115 @@
+package atlasexec_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"ariga.io/atlas-go-sdk/atlasexec/atlasexectest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	for s, expect := range map[string]*atlasexec.Version{
+		"v0.32.0":        {Version: "0.32.0"},
+		"0.32.0":         {Version: "0.32.0"},
+		"v0.32.1-abcdef": {Version: "0.32.1", SHA: "abcdef"},
+		"atlas version v0.14.1-abcdef-canary\nhttps://github.com/ariga/atlas/releases/latest": {Version: "0.14.1", SHA: "abcdef", Canary: true},
+	} {
+		v, err := atlasexec.ParseVersion(s)
+		require.NoError(t, err)
+		require.Equal(t, expect, v, s)
+	}
+	_, err := atlasexec.ParseVersion("latest")
+	require.EqualError(t, err, `atlasexec: invalid version "latest"`)
+}
+
+func TestVersion_Compare(t *testing.T) {
+	v := func(s string) atlasexec.Version {
+		v, err := atlasexec.ParseVersion(s)
+		require.NoError(t, err)
+		return *v
+	}
+	require.Equal(t, 0, v("v0.32.0").Compare(v("v0.32.0")))
+	require.Equal(t, 0, v("v0.32.0-abc").Compare(v("v0.32.0-def")))
+	require.Equal(t, -1, v("v0.9.0").Compare(v("v0.10.0")))
+	require.Equal(t, 1, v("v1.0.0").Compare(v("v0.99.99")))
+	require.Equal(t, -1, v("v0.32.1-abc-canary").Compare(v("v0.32.1")))
+	require.Equal(t, 1, v("v0.32.1-abc-canary").Compare(v("v0.32.0")))
+
+	require.True(t, v("v0.32.0").AtLeast("v0.31.0"))
+	require.True(t, v("v0.32.0").AtLeast("0.32.0"))
+	require.True(t, v("v0.32.0-abc-canary").AtLeast("v0.32.0"))
+	require.False(t, v("v0.30.9").AtLeast("v0.31.0"))
+	require.False(t, v("v0.32.0").AtLeast("invalid"))
+}
+
+func TestWithVersionCheck(t *testing.T) {
+	c, ex := atlasexectest.NewClient(t, atlasexec.WithVersionCheck())
+	probe := ex.On("version").Stdout("atlas version v0.30.0")
+	ex.On("schema", "test").Stdout("ok")
+
+	// Commands without requirements do not probe the version.
+	ex.On("logout")
+	require.NoError(t, c.Logout(context.Background()))
+	require.Zero(t, probe.Count())
+
+	_, err := c.SchemaLint(context.Background(), &atlasexec.SchemaLintParams{})
+	require.ErrorIs(t, err, atlasexec.ErrUnsupportedVersion)
+	var verErr *atlasexec.UnsupportedVersionError
+	require.ErrorAs(t, err, &verErr)
+	require.Equal(t, "schema lint", verErr.Command)
+	require.Equal(t, "v0.31.0", verErr.Required)
+	require.Equal(t, "0.30.0", verErr.Actual.Version)
+	require.EqualError(t, err, `atlasexec: command "schema lint" requires atlas-cli v0.31.0 or later, but v0.30.0 is used`)
+
+	// Supported commands are executed, and the version is probed once.
+	out, err := c.With().SchemaTest(context.Background(), &atlasexec.SchemaTestParams{})
+	require.NoError(t, err)
+	require.Equal(t, "ok", out)
+	require.Equal(t, 1, probe.Count())
+
+	// Sub-commands inherit the requirements of their parents.
+	c, ex = atlasexectest.NewClient(t, atlasexec.WithVersionCheck())
+	ex.On("version").Stdout("atlas version v0.20.0")
+	_, err = c.SchemaPlanLint(context.Background(), &atlasexec.SchemaPlanLintParams{File: "1.plan.hcl"})
+	require.ErrorAs(t, err, &verErr)
+	require.Equal(t, "schema plan lint", verErr.Command)
+	require.Equal(t, "v0.25.0", verErr.Required)
+}
+
+func TestWithVersionCheck_Concurrent(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		probes  atomic.Int32
+	)
+	c, err := atlasexec.NewClient("", "atlas", atlasexec.WithVersionCheck(), atlasexec.WithExecutor(executorFunc(func(_ context.Context, inv *atlasexec.Invocation) error {
+		if inv.Args[0] == "version" && probes.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		_, err := io.WriteString(inv.Stdout, "atlas version v0.32.0")
+		return err
+	})))
+	require.NoError(t, err)
+	done := make(chan error)
+	go func() {
+		_, err := c.SchemaTest(context.Background(), &atlasexec.SchemaTestParams{})
+		done <- err
+	}()
+	<-started
+	// Commands waiting for the probe respect their context.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.SchemaTest(ctx, &atlasexec.SchemaTestParams{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	close(release)
+	require.NoError(t, <-done)
+	_, err = c.SchemaTest(context.Background(), &atlasexec.SchemaTestParams{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, probes.Load(), "the version is probed once")
+}